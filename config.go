@@ -1,6 +1,9 @@
 package manager
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // AppConfig ...
 type AppConfig struct {
@@ -12,12 +15,121 @@ type ManagerConfig struct {
 	Log struct {
 		Level string `json:"level"`
 	} `json:"log"`
+	Dbs               map[string]*ManagerDBConfig               `json:"dbs"`
+	Redis             map[string]*ManagerRedisConfig            `json:"redis"`
+	NSQProducers      map[string]*ManagerNSQProducerConfig      `json:"nsq_producers"`
+	NSQConsumers      map[string]*ManagerNSQConsumerConfig      `json:"nsq_consumers"`
+	RabbitmqProducers map[string]*ManagerRabbitmqProducerConfig `json:"rabbitmq_producers"`
+	RabbitmqConsumers map[string]*ManagerRabbitmqConsumerConfig `json:"rabbitmq_consumers"`
+	Webs              map[string]*ManagerWebConfig              `json:"webs"`
+	WorkLists         map[string]*ManagerWorkListConfig         `json:"work_lists"`
+	BulkWorkLists     map[string]*ManagerBulkWorkListConfig     `json:"bulk_work_lists"`
+	// Components has components of any registered type, given on their type field
+	Components map[string]json.RawMessage `json:"components"`
 }
 
-// NewConfig ...
-func NewConfig() (*AppConfig, IConfig, error) {
+// ManagerComponentConfig has the options shared by all the components declared on the configuration
+type ManagerComponentConfig struct {
+	DependsOn []string `json:"depends_on"`
+}
+
+// ManagerDBConfig ...
+type ManagerDBConfig struct {
+	DBConfig
+	ManagerComponentConfig
+}
+
+// ManagerRedisConfig ...
+type ManagerRedisConfig struct {
+	RedisConfig
+	ManagerComponentConfig
+}
+
+// ManagerNSQProducerConfig ...
+type ManagerNSQProducerConfig struct {
+	NSQConfig
+	ManagerComponentConfig
+}
+
+// ManagerNSQConsumerConfig ...
+type ManagerNSQConsumerConfig struct {
+	NSQConfig
+	ManagerComponentConfig
+	Handler string `json:"handler"`
+}
+
+// ManagerRabbitmqProducerConfig ...
+type ManagerRabbitmqProducerConfig struct {
+	RabbitmqConfig
+	ManagerComponentConfig
+}
+
+// ManagerRabbitmqConsumerConfig ...
+type ManagerRabbitmqConsumerConfig struct {
+	RabbitmqConfig
+	ManagerComponentConfig
+	Queue      string `json:"queue"`
+	BindingKey string `json:"binding_key"`
+	Tag        string `json:"tag"`
+	Handler    string `json:"handler"`
+}
+
+// ManagerWebConfig ...
+type ManagerWebConfig struct {
+	ManagerComponentConfig
+	// Type is one of http (default), echo or web
+	Type   string                   `json:"type"`
+	Host   string                   `json:"host"`
+	Routes []*ManagerWebRouteConfig `json:"routes"`
+}
+
+// ManagerWebRouteConfig ...
+type ManagerWebRouteConfig struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Handler     string   `json:"handler"`
+	Middlewares []string `json:"middlewares"`
+}
+
+// ManagerWorkListConfig ...
+type ManagerWorkListConfig struct {
+	WorkListConfig
+	ManagerComponentConfig
+	Handler                     string `json:"handler"`
+	RecoverHandler              string `json:"recover_handler"`
+	RecoverWastedRetriesHandler string `json:"recover_wasted_retries_handler"`
+}
+
+// ManagerBulkWorkListConfig ...
+type ManagerBulkWorkListConfig struct {
+	BulkWorkListConfig
+	ManagerComponentConfig
+	Handler                     string `json:"handler"`
+	RecoverHandler              string `json:"recover_handler"`
+	RecoverWastedRetriesHandler string `json:"recover_wasted_retries_handler"`
+}
+
+// options returns the component options of the declared component
+func (config *ManagerComponentConfig) options() []ComponentOption {
+	options := make([]ComponentOption, 0)
+	if len(config.DependsOn) > 0 {
+		options = append(options, DependsOn(config.DependsOn...))
+	}
+
+	return options
+}
+
+// NewConfig loads the application config, layered from the base file /config/app.json, the environment
+// file /config/app.{env}.json, the environment variables with the MANAGER prefix and the options.
+// the files can also be yaml (.yaml or .yml) or toml (.toml), the first existing one is used
+func NewConfig(options ...ConfigOption) (*AppConfig, IConfig, error) {
 	appConfig := &AppConfig{}
-	simpleConfig, err := NewSimpleConfig(fmt.Sprintf("/config/app.%s.json", GetEnv()), appConfig)
+	options = append([]ConfigOption{
+		WithBaseConfigFile(findConfigFile("/config/app")),
+		WithEnvPrefix(defaultEnvPrefix),
+	}, options...)
+
+	simpleConfig, err := NewSimpleConfig(findConfigFile(fmt.Sprintf("/config/app.%s", GetEnv())), appConfig, options...)
 
 	return appConfig, simpleConfig, err
 }
@@ -0,0 +1,174 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFormat ...
+type ConfigFormat string
+
+const (
+	ConfigFormatJSON ConfigFormat = "json"
+	ConfigFormatYAML ConfigFormat = "yaml"
+	ConfigFormatTOML ConfigFormat = "toml"
+)
+
+// configExtensions are the extensions tried when looking for a config file, by order
+var configExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// GetConfigFormat gets the format of the config file by its extension, json is used for unknown extensions
+func GetConfigFormat(fileName string) ConfigFormat {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return ConfigFormatYAML
+	case ".toml":
+		return ConfigFormatTOML
+	default:
+		return ConfigFormatJSON
+	}
+}
+
+// findConfigFile gets the first existing config file with the name and one of the known extensions,
+// when none exists the json file is returned
+func findConfigFile(name string) string {
+	for _, extension := range configExtensions {
+		if Exists(resolveFile(name + extension)) {
+			return name + extension
+		}
+	}
+
+	return name + ".json"
+}
+
+// decodeConfigValues decodes the config file content, in the format of the file, into a map
+func decodeConfigValues(format ConfigFormat, data []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return values, nil
+	}
+
+	switch format {
+	case ConfigFormatYAML:
+		yamlValues := make(map[interface{}]interface{})
+		if err := yaml.Unmarshal(data, &yamlValues); err != nil {
+			return nil, err
+		}
+		values = normalize(yamlValues).(map[string]interface{})
+	case ConfigFormatTOML:
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		values = normalize(values).(map[string]interface{})
+	default:
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// decodeConfig decodes the config file content into the object, by its json tags in all the formats,
+// as the config is reloaded and saved by its json tags
+func decodeConfig(format ConfigFormat, data []byte, obj interface{}) error {
+	if format == ConfigFormatJSON {
+		return json.Unmarshal(data, obj)
+	}
+
+	values, err := decodeConfigValues(format, data)
+	if err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonBytes, obj)
+}
+
+// encodeConfig encodes the object in the format, by its json tags in all the formats
+func encodeConfig(format ConfigFormat, obj interface{}) ([]byte, error) {
+	jsonBytes, err := json.MarshalIndent(obj, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	if format == ConfigFormatJSON {
+		return jsonBytes, nil
+	}
+
+	// the numbers are kept, so that the integers aren't encoded as floats
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+
+	values := make(map[string]interface{})
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("the config must be an object to be encoded as %s: %w", format, err)
+	}
+	values = withNumbers(values).(map[string]interface{})
+
+	switch format {
+	case ConfigFormatYAML:
+		return yaml.Marshal(values)
+	case ConfigFormatTOML:
+		// toml doesn't have null values
+		return toml.Marshal(withoutNulls(values))
+	}
+
+	return jsonBytes, nil
+}
+
+// withNumbers converts the json numbers to integers or floats
+func withNumbers(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			typed[key] = withNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = withNumbers(item)
+		}
+	case json.Number:
+		if integer, err := typed.Int64(); err == nil {
+			return integer
+		}
+		float, _ := typed.Float64()
+		return float
+	}
+
+	return value
+}
+
+// withoutNulls removes the null values of the maps
+func withoutNulls(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			if item != nil {
+				result[key] = withoutNulls(item)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(typed))
+		for _, item := range typed {
+			if item != nil {
+				result = append(result, withoutNulls(item))
+			}
+		}
+		return result
+	default:
+		return value
+	}
+}
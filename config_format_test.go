@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// taggedConfig has different names on the json, yaml and toml tags, the json ones are used for all the formats
+type taggedConfig struct {
+	MaxConns int    `json:"maxConns" yaml:"max_conns" toml:"max_conns"`
+	Name     string `json:"name" yaml:"config_name" toml:"config_name"`
+}
+
+func TestConfigFormatsUseTheJSONTags(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{file: "config.json", content: `{"maxConns": 5, "name": "db"}`},
+		{file: "config.yaml", content: "maxConns: 5\nname: db\n"},
+		{file: "config.yml", content: "maxConns: 5\nname: db\n"},
+		{file: "config.toml", content: "maxConns = 5\nname = \"db\"\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), test.file)
+			if err := os.WriteFile(file, []byte(test.content), 0644); err != nil {
+				t.Fatalf("error writing the config file: %s", err)
+			}
+
+			read := &taggedConfig{}
+			if _, err := ReadFile(file, read); err != nil {
+				t.Fatalf("error reading the config file: %s", err)
+			}
+
+			loaded := &taggedConfig{}
+			config, err := NewSimpleConfig(file, loaded)
+			if err != nil {
+				t.Fatalf("error loading the config file: %s", err)
+			}
+
+			for name, obj := range map[string]*taggedConfig{"read": read, "loaded": loaded} {
+				if obj.MaxConns != 5 || obj.Name != "db" {
+					t.Errorf("the %s config is %+v, expected max conns 5 and name db", name, obj)
+				}
+			}
+
+			if value := config.GetInt("maxConns"); value != 5 {
+				t.Errorf("the max conns key is %d, expected 5", value)
+			}
+
+			if err := WriteFile(file, &taggedConfig{MaxConns: 7, Name: "saved"}); err != nil {
+				t.Fatalf("error writing the config: %s", err)
+			}
+
+			if err := config.Reload(); err != nil {
+				t.Fatalf("error reloading the config: %s", err)
+			}
+
+			if loaded.MaxConns != 7 || loaded.Name != "saved" {
+				t.Errorf("the reloaded config is %+v, expected max conns 7 and name saved", loaded)
+			}
+		})
+	}
+}
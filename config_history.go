@@ -0,0 +1,167 @@
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrConfigVersionNotFound is returned when getting a version that isn't on the history
+var ErrConfigVersionNotFound = errors.New("config version not found")
+
+// ConfigVersion is a saved version of a config file
+type ConfigVersion struct {
+	ID        int             `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Author    string          `json:"author"`
+	Message   string          `json:"message,omitempty"`
+	Diff      *ConfigDiff     `json:"diff"`
+	Content   json.RawMessage `json:"content"`
+}
+
+// IConfigHistoryStore keeps the versions of a config, the store sets the id of the appended versions
+type IConfigHistoryStore interface {
+	Append(version *ConfigVersion) error
+	List() ([]*ConfigVersion, error)
+	Get(id int) (*ConfigVersion, error)
+}
+
+// MemoryConfigHistoryStore ...
+type MemoryConfigHistoryStore struct {
+	versions []*ConfigVersion
+	mux      sync.RWMutex
+}
+
+// NewMemoryConfigHistoryStore ...
+func NewMemoryConfigHistoryStore() *MemoryConfigHistoryStore {
+	return &MemoryConfigHistoryStore{
+		versions: make([]*ConfigVersion, 0),
+	}
+}
+
+// Append ...
+func (store *MemoryConfigHistoryStore) Append(version *ConfigVersion) error {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+
+	version.ID = len(store.versions) + 1
+	store.versions = append(store.versions, version)
+
+	return nil
+}
+
+// List ...
+func (store *MemoryConfigHistoryStore) List() ([]*ConfigVersion, error) {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+
+	return append([]*ConfigVersion{}, store.versions...), nil
+}
+
+// Get ...
+func (store *MemoryConfigHistoryStore) Get(id int) (*ConfigVersion, error) {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+
+	if id < 1 || id > len(store.versions) {
+		return nil, fmt.Errorf("%w [ id: %d ]", ErrConfigVersionNotFound, id)
+	}
+
+	return store.versions[id-1], nil
+}
+
+// FileConfigHistoryStore keeps the versions on a file, with one version encoded as json by line
+type FileConfigHistoryStore struct {
+	file string
+	mux  sync.Mutex
+}
+
+// NewFileConfigHistoryStore ...
+func NewFileConfigHistoryStore(file string) *FileConfigHistoryStore {
+	return &FileConfigHistoryStore{
+		file: file,
+	}
+}
+
+// Append ...
+func (store *FileConfigHistoryStore) Append(version *ConfigVersion) error {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+
+	versions, err := store.read()
+	if err != nil {
+		return err
+	}
+	version.ID = len(versions) + 1
+
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(store.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// List ...
+func (store *FileConfigHistoryStore) List() ([]*ConfigVersion, error) {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+
+	return store.read()
+}
+
+// Get ...
+func (store *FileConfigHistoryStore) Get(id int) (*ConfigVersion, error) {
+	versions, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if id < 1 || id > len(versions) {
+		return nil, fmt.Errorf("%w [ id: %d ]", ErrConfigVersionNotFound, id)
+	}
+
+	return versions[id-1], nil
+}
+
+// read ...
+func (store *FileConfigHistoryStore) read() ([]*ConfigVersion, error) {
+	versions := make([]*ConfigVersion, 0)
+
+	file, err := os.Open(store.file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return versions, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		version := &ConfigVersion{}
+		if err := json.Unmarshal(scanner.Bytes(), version); err != nil {
+			return nil, fmt.Errorf("invalid config version [ file: %s, line: %d ]: %w", store.file, len(versions)+1, err)
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, scanner.Err()
+}
@@ -0,0 +1,181 @@
+package manager
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// redacted replaces the secrets when the config is dumped
+const redacted = "******"
+
+// secretPattern matches the secret references, ${scheme:reference}
+var secretPattern = regexp.MustCompile(`\$\{([a-zA-Z][a-zA-Z0-9_-]*):([^}]*)\}`)
+
+// ISecretResolver resolves the secret references of a scheme (ex: ${env:DB_PASSWORD})
+type ISecretResolver interface {
+	Resolve(reference string) (string, error)
+}
+
+// SecretResolverFunc ...
+type SecretResolverFunc func(reference string) (string, error)
+
+// Resolve ...
+func (f SecretResolverFunc) Resolve(reference string) (string, error) {
+	return f(reference)
+}
+
+var (
+	secretResolvers = map[string]ISecretResolver{
+		"env":    SecretResolverFunc(resolveEnvSecret),
+		"file":   SecretResolverFunc(resolveFileSecret),
+		"base64": SecretResolverFunc(resolveBase64Secret),
+	}
+	secretResolversMux sync.RWMutex
+)
+
+// RegisterSecretResolver registers the resolver of a scheme for all the configs, replacing the one with the same scheme
+func RegisterSecretResolver(scheme string, resolver ISecretResolver) {
+	secretResolversMux.Lock()
+	defer secretResolversMux.Unlock()
+
+	secretResolvers[scheme] = resolver
+}
+
+// resolveEnvSecret ...
+func resolveEnvSecret(reference string) (string, error) {
+	value, exists := os.LookupEnv(reference)
+	if !exists {
+		return "", fmt.Errorf("environment variable not found [ name: %s ]", reference)
+	}
+
+	return value, nil
+}
+
+// resolveFileSecret reads the file, without the trailing new line
+func resolveFileSecret(reference string) (string, error) {
+	data, err := os.ReadFile(reference)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveBase64Secret ...
+func resolveBase64Secret(reference string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(reference)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// configSecret is a value of the config with secret references
+type configSecret struct {
+	path     []string
+	raw      string
+	resolved string
+}
+
+// key ...
+func (secret *configSecret) key() string {
+	return strings.Join(secret.path, ".")
+}
+
+// resolver gets the resolver of the scheme, the ones of the config have precedence over the registered ones
+func (simple *SimpleConfig) resolver(scheme string) (ISecretResolver, bool) {
+	if resolver, exists := simple.resolvers[scheme]; exists {
+		return resolver, true
+	}
+
+	secretResolversMux.RLock()
+	defer secretResolversMux.RUnlock()
+
+	resolver, exists := secretResolvers[scheme]
+	return resolver, exists
+}
+
+// resolveSecrets replaces the secret references of the string values, returning the values with secrets
+func (simple *SimpleConfig) resolveSecrets(path []string, value interface{}, secrets *[]*configSecret) (interface{}, error) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			resolved, err := simple.resolveSecrets(append(path[:len(path):len(path)], key), item, secrets)
+			if err != nil {
+				return nil, err
+			}
+			typed[key] = resolved
+		}
+	case []interface{}:
+		for i, item := range typed {
+			resolved, err := simple.resolveSecrets(append(path[:len(path):len(path)], strconv.Itoa(i)), item, secrets)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = resolved
+		}
+	case string:
+		if !secretPattern.MatchString(typed) {
+			return typed, nil
+		}
+
+		var err error
+		resolved := secretPattern.ReplaceAllStringFunc(typed, func(reference string) string {
+			if err != nil {
+				return ""
+			}
+
+			match := secretPattern.FindStringSubmatch(reference)
+			resolver, exists := simple.resolver(match[1])
+			if !exists {
+				err = fmt.Errorf("unknown secret resolver [ scheme: %s ]", match[1])
+				return ""
+			}
+
+			secret, errResolve := resolver.Resolve(match[2])
+			if errResolve != nil {
+				err = fmt.Errorf("error resolving the secret [ scheme: %s, key: %s ]: %w", match[1], strings.Join(path, "."), errResolve)
+				return ""
+			}
+
+			return secret
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		*secrets = append(*secrets, &configSecret{path: path, raw: typed, resolved: resolved})
+		return resolved, nil
+	}
+
+	return value, nil
+}
+
+// replaceConfigValue replaces the value on the path when it has the expected value
+func replaceConfigValue(value interface{}, path []string, expected, replacement interface{}) interface{} {
+	if len(path) == 0 {
+		if value == expected {
+			return replacement
+		}
+		return value
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if item, exists := typed[path[0]]; exists {
+			typed[path[0]] = replaceConfigValue(item, path[1:], expected, replacement)
+		}
+	case []interface{}:
+		if i, err := strconv.Atoi(path[0]); err == nil && i >= 0 && i < len(typed) {
+			typed[i] = replaceConfigValue(typed[i], path[1:], expected, replacement)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,307 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tagDefault  = "default"
+	tagValidate = "validate"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ConfigViolation ...
+type ConfigViolation struct {
+	Key     string `json:"key"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ConfigValidationError has all the violations of the validate tags of a config, by key path
+type ConfigValidationError struct {
+	Violations []*ConfigViolation `json:"violations"`
+}
+
+// Error ...
+func (e *ConfigValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = fmt.Sprintf("%s %s", violation.Key, violation.Message)
+	}
+
+	return fmt.Sprintf("invalid config with %d violation(s): %s", len(e.Violations), strings.Join(messages, "; "))
+}
+
+// add ...
+func (e *ConfigValidationError) add(key, rule, message string, args ...interface{}) {
+	e.Violations = append(e.Violations, &ConfigViolation{Key: key, Rule: rule, Message: fmt.Sprintf(message, args...)})
+}
+
+// ValidateConfig sets the empty fields of the object with their default tag (ex: `default:"1s"`),
+// and validates the fields with their validate tag, with the rules required, min, max and oneof
+// (ex: `validate:"required,min=1,oneof=fifo lifo"`). the min and max rules compare the length of
+// the strings, slices and maps, and the oneof rule ignores the empty values, that are only rejected by required.
+// all the violations are returned in a *ConfigValidationError
+func ValidateConfig(obj interface{}) error {
+	return validateConfig("", obj)
+}
+
+// validateConfig validates the object, with the key paths of the violations below the path
+func validateConfig(path string, obj interface{}) error {
+	errs := &ConfigValidationError{}
+	walkConfig(path, reflect.ValueOf(obj), errs)
+
+	if len(errs.Violations) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// walkConfig applies the default tags and validates the validate tags of the value and of its fields
+func walkConfig(path string, value reflect.Value, errs *ConfigValidationError) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+
+			fieldPath := path
+			if !field.Anonymous || name != "" {
+				if name == "" {
+					name = field.Name
+				}
+				fieldPath = joinConfigKey(path, name)
+			}
+
+			fieldValue := value.Field(i)
+			if tag, ok := field.Tag.Lookup(tagDefault); ok && fieldValue.CanSet() && fieldValue.IsZero() {
+				if err := setConfigDefault(fieldValue, tag); err != nil {
+					errs.add(fieldPath, tagDefault, "has an invalid default %q: %s", tag, err)
+				}
+			}
+
+			if tag := field.Tag.Get(tagValidate); tag != "" {
+				validateConfigValue(fieldPath, fieldValue, tag, errs)
+			}
+
+			walkConfig(fieldPath, fieldValue, errs)
+		}
+
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+
+		for _, key := range keys {
+			// the map values aren't addressable, so the defaults are set on a copy
+			item := reflect.New(value.Type().Elem()).Elem()
+			item.Set(value.MapIndex(key))
+			walkConfig(joinConfigKey(path, fmt.Sprint(key)), item, errs)
+			value.SetMapIndex(key, item)
+		}
+
+	case reflect.Slice, reflect.Array:
+		switch value.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			for i := 0; i < value.Len(); i++ {
+				walkConfig(joinConfigKey(path, strconv.Itoa(i)), value.Index(i), errs)
+			}
+		}
+	}
+}
+
+// joinConfigKey ...
+func joinConfigKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// setConfigDefault sets the value from the default tag
+func setConfigDefault(value reflect.Value, tag string) error {
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(tag)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(tag)
+	case reflect.Bool:
+		converted, err := strconv.ParseBool(tag)
+		if err != nil {
+			return err
+		}
+		value.SetBool(converted)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		converted, err := strconv.ParseInt(tag, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(converted)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		converted, err := strconv.ParseUint(tag, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(converted)
+	case reflect.Float32, reflect.Float64:
+		converted, err := strconv.ParseFloat(tag, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(converted)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(tag, "[") {
+			items := strings.Split(tag, ",")
+			converted := reflect.MakeSlice(value.Type(), len(items), len(items))
+			for i, item := range items {
+				converted.Index(i).SetString(strings.TrimSpace(item))
+			}
+			value.Set(converted)
+			return nil
+		}
+		return json.Unmarshal([]byte(tag), value.Addr().Interface())
+	default:
+		return json.Unmarshal([]byte(tag), value.Addr().Interface())
+	}
+
+	return nil
+}
+
+// validateConfigValue validates the value with the rules of the validate tag
+func validateConfigValue(path string, value reflect.Value, tag string, errs *ConfigValidationError) {
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		if name == "required" {
+			if isEmptyConfigValue(value) {
+				// the other rules of an empty value would only repeat the violation
+				errs.add(path, name, "is required")
+				return
+			}
+			continue
+		}
+
+		// the other rules validate the value pointed
+		current := value
+		for current.Kind() == reflect.Ptr || current.Kind() == reflect.Interface {
+			if current.IsNil() {
+				break
+			}
+			current = current.Elem()
+		}
+		if (current.Kind() == reflect.Ptr || current.Kind() == reflect.Interface) && current.IsNil() {
+			continue
+		}
+
+		switch name {
+		case "min", "max":
+			number, limit, err := compareConfigValue(current, param)
+			if err != nil {
+				errs.add(path, name, "has an invalid rule %q: %s", rule, err)
+				continue
+			}
+
+			if name == "min" && number < limit {
+				errs.add(path, name, "must be at least %s", param)
+			}
+			if name == "max" && number > limit {
+				errs.add(path, name, "must be at most %s", param)
+			}
+
+		case "oneof":
+			if isEmptyConfigValue(current) {
+				continue
+			}
+
+			options := strings.Fields(param)
+			text := fmt.Sprint(current.Interface())
+			found := false
+			for _, option := range options {
+				if option == text {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				errs.add(path, name, "must be one of [ %s ], but is %q", strings.Join(options, ", "), text)
+			}
+
+		default:
+			errs.add(path, name, "has an unknown rule %q", name)
+		}
+	}
+}
+
+// compareConfigValue gets the number to compare of the value (the length of the strings, slices and maps) and the limit
+func compareConfigValue(value reflect.Value, param string) (float64, float64, error) {
+	if value.Type() == durationType {
+		limit, err := time.ParseDuration(param)
+		if err != nil {
+			integer, errInt := strconv.ParseInt(param, 10, 64)
+			if errInt != nil {
+				return 0, 0, err
+			}
+			limit = time.Duration(integer)
+		}
+		return float64(value.Int()), float64(limit), nil
+	}
+
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), limit, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), limit, nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), limit, nil
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), limit, nil
+	default:
+		return 0, 0, fmt.Errorf("the type %s can't be compared", value.Type())
+	}
+}
+
+// isEmptyConfigValue checks if the value is zero or, for strings, slices and maps, if it is empty
+func isEmptyConfigValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	default:
+		return value.IsZero()
+	}
+}
@@ -0,0 +1,128 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// validatedConfig ...
+type validatedConfig struct {
+	Name     string            `json:"name" validate:"required"`
+	Mode     string            `json:"mode" default:"fifo" validate:"oneof=fifo lifo"`
+	Workers  int               `json:"workers" default:"2" validate:"min=1,max=10"`
+	Timeout  time.Duration     `json:"timeout" default:"1s" validate:"min=1ms"`
+	Hosts    []string          `json:"hosts" default:"a, b"`
+	Enabled  bool              `json:"enabled" default:"true"`
+	Children []*validatedChild `json:"children"`
+	Labels   map[string]*validatedChild
+}
+
+// validatedChild ...
+type validatedChild struct {
+	Port int `json:"port" default:"80" validate:"max=65535"`
+}
+
+func TestValidateConfigDefaults(t *testing.T) {
+	config := &validatedConfig{
+		Name:     "works",
+		Workers:  3,
+		Children: []*validatedChild{{}, {Port: 8080}},
+		Labels:   map[string]*validatedChild{"main": {}},
+	}
+
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("error validating the config: %s", err)
+	}
+
+	expected := &validatedConfig{
+		Name:     "works",
+		Mode:     "fifo",
+		Workers:  3,
+		Timeout:  time.Second,
+		Hosts:    []string{"a", "b"},
+		Enabled:  true,
+		Children: []*validatedChild{{Port: 80}, {Port: 8080}},
+		Labels:   map[string]*validatedChild{"main": {Port: 80}},
+	}
+
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("the config with the defaults is %+v, expected %+v", config, expected)
+	}
+}
+
+func TestValidateConfigViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *validatedConfig
+		expected []*ConfigViolation
+	}{
+		{
+			name:     "required",
+			config:   &validatedConfig{},
+			expected: []*ConfigViolation{{Key: "name", Rule: "required"}},
+		},
+		{
+			name:     "oneof",
+			config:   &validatedConfig{Name: "works", Mode: "random"},
+			expected: []*ConfigViolation{{Key: "mode", Rule: "oneof"}},
+		},
+		{
+			name:     "min and max",
+			config:   &validatedConfig{Name: "works", Workers: 11, Timeout: time.Microsecond},
+			expected: []*ConfigViolation{{Key: "workers", Rule: "max"}, {Key: "timeout", Rule: "min"}},
+		},
+		{
+			name:     "nested",
+			config:   &validatedConfig{Name: "works", Children: []*validatedChild{{}, {Port: 70000}}, Labels: map[string]*validatedChild{"main": {Port: 70000}}},
+			expected: []*ConfigViolation{{Key: "children.1.port", Rule: "max"}, {Key: "Labels.main.port", Rule: "max"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var validationErr *ConfigValidationError
+			if err := ValidateConfig(test.config); !errors.As(err, &validationErr) {
+				t.Fatalf("validating got the error %v, expected a *ConfigValidationError", err)
+			}
+
+			if len(validationErr.Violations) != len(test.expected) {
+				t.Fatalf("got the violations %s, expected %d", validationErr, len(test.expected))
+			}
+
+			for i, violation := range validationErr.Violations {
+				if violation.Key != test.expected[i].Key || violation.Rule != test.expected[i].Rule {
+					t.Errorf("got the violation %s of %s, expected %s of %s", violation.Rule, violation.Key, test.expected[i].Rule, test.expected[i].Key)
+				}
+			}
+		})
+	}
+}
+
+func TestReloadKeepsTheValidConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"name": "works", "workers": 4}`), 0644); err != nil {
+		t.Fatalf("error writing the config file: %s", err)
+	}
+
+	obj := &validatedConfig{}
+	config, err := NewSimpleConfig(file, obj)
+	if err != nil {
+		t.Fatalf("error loading the config: %s", err)
+	}
+
+	if err := os.WriteFile(file, []byte(`{"name": "works", "workers": 20}`), 0644); err != nil {
+		t.Fatalf("error writing the config file: %s", err)
+	}
+
+	if err := config.Reload(); err == nil {
+		t.Errorf("reloaded the config with an invalid number of workers")
+	}
+
+	if obj.Workers != 4 || config.GetInt("workers") != 4 {
+		t.Errorf("the config has %d workers after the invalid reload, expected 4", obj.Workers)
+	}
+}
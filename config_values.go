@@ -0,0 +1,225 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// configValues keeps the merged configuration, encoded as json, and its object, with the accessors
+// of the configurations (the SimpleConfig and the RemoteConfig)
+type configValues struct {
+	subscribers []func(diff *ConfigDiff)
+	obj         interface{}
+	defaults    []byte
+	bytes       []byte
+	viper       *viper.Viper
+	mux         sync.RWMutex
+}
+
+// newConfigValues keeps the values set on the object before loading as defaults on each reload
+func newConfigValues(obj interface{}) *configValues {
+	values := &configValues{obj: obj}
+	if obj != nil {
+		values.defaults, _ = json.Marshal(obj)
+	}
+
+	return values
+}
+
+// Get ...
+func (values *configValues) Get(key string) interface{} {
+	return values.getViper().Get(key)
+}
+
+// GetString ...
+func (values *configValues) GetString(key string) string {
+	return values.getViper().GetString(key)
+}
+
+// GetBool ...
+func (values *configValues) GetBool(key string) bool {
+	return values.getViper().GetBool(key)
+}
+
+// GetInt ...
+func (values *configValues) GetInt(key string) int {
+	return values.getViper().GetInt(key)
+}
+
+// GetInt64 ...
+func (values *configValues) GetInt64(key string) int64 {
+	return values.getViper().GetInt64(key)
+}
+
+// GetFloat64 ...
+func (values *configValues) GetFloat64(key string) float64 {
+	return values.getViper().GetFloat64(key)
+}
+
+// GetTime ...
+func (values *configValues) GetTime(key string) time.Time {
+	return values.getViper().GetTime(key)
+}
+
+// GetDuration ...
+func (values *configValues) GetDuration(key string) time.Duration {
+	return values.getViper().GetDuration(key)
+}
+
+// GetStringSlice ...
+func (values *configValues) GetStringSlice(key string) []string {
+	return values.getViper().GetStringSlice(key)
+}
+
+// GetStringMap ...
+func (values *configValues) GetStringMap(key string) map[string]interface{} {
+	return values.getViper().GetStringMap(key)
+}
+
+// GetStringMapString ...
+func (values *configValues) GetStringMapString(key string) map[string]string {
+	return values.getViper().GetStringMapString(key)
+}
+
+// GetStringMapStringSlice ...
+func (values *configValues) GetStringMapStringSlice(key string) map[string][]string {
+	return values.getViper().GetStringMapStringSlice(key)
+}
+
+// IsSet checks if the key is on the configuration
+func (values *configValues) IsSet(key string) bool {
+	_, exists := values.lookup(key)
+	return exists
+}
+
+// UnmarshalKey decodes the section of the key into the object (ex: a *DBConfig from "manager.dbs.main"),
+// with the default tags applied and the validate tags validated
+func (values *configValues) UnmarshalKey(key string, obj interface{}) error {
+	value, exists := values.lookup(key)
+	if !exists {
+		return fmt.Errorf("%w [ key: %s ]", ErrConfigKeyNotFound, key)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, obj); err != nil {
+		return err
+	}
+
+	return validateConfig(key, obj)
+}
+
+// lookup gets the value of the key, with the levels separated by dots
+func (values *configValues) lookup(key string) (interface{}, bool) {
+	values.mux.RLock()
+	data := values.bytes
+	values.mux.RUnlock()
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, false
+	}
+
+	return lookupConfigValue(decoded, key)
+}
+
+// GetObj ...
+func (values *configValues) GetObj() interface{} {
+	values.mux.RLock()
+	defer values.mux.RUnlock()
+
+	return values.obj
+}
+
+// Set ...
+func (values *configValues) Set(config interface{}) {
+	values.mux.Lock()
+	defer values.mux.Unlock()
+
+	values.obj = config
+}
+
+// OnChange adds a handler called with the changed keys after each reload that changes the configuration
+func (values *configValues) OnChange(handler func(diff *ConfigDiff)) {
+	values.mux.Lock()
+	defer values.mux.Unlock()
+
+	values.subscribers = append(values.subscribers, handler)
+}
+
+// decode decodes the configuration into a new object, with the default tags applied, and validates it
+func (values *configValues) decode(data []byte, validators ...func(obj interface{}) error) (interface{}, error) {
+	current := values.GetObj()
+	if current == nil {
+		return nil, nil
+	}
+
+	value := reflect.ValueOf(current)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil, fmt.Errorf("the config object must be a pointer [ type: %T ]", current)
+	}
+
+	obj := reflect.New(value.Elem().Type()).Interface()
+	if len(values.defaults) > 0 {
+		json.Unmarshal(values.defaults, obj)
+	}
+
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateConfig(obj); err != nil {
+		return nil, err
+	}
+
+	for _, validator := range validators {
+		if err := validator(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	return obj, nil
+}
+
+// apply uses the configuration and its decoded object, calling the update holding the lock (ex: to keep the secrets),
+// and returns the changes with the subscribers to notify, without changes on the first configuration
+func (values *configValues) apply(data []byte, obj interface{}, update func()) (*ConfigDiff, []func(diff *ConfigDiff)) {
+	values.mux.Lock()
+	previous := values.bytes
+	values.bytes = data
+	values.viper = loadViper(data)
+	if obj != nil {
+		reflect.ValueOf(values.obj).Elem().Set(reflect.ValueOf(obj).Elem())
+	}
+	if update != nil {
+		update()
+	}
+	subscribers := append([]func(diff *ConfigDiff){}, values.subscribers...)
+	values.mux.Unlock()
+
+	if previous == nil {
+		return nil, nil
+	}
+
+	if diff := newConfigDiff(previous, data); len(diff.Changes) > 0 {
+		return diff, subscribers
+	}
+
+	return nil, nil
+}
+
+// getViper ...
+func (values *configValues) getViper() *viper.Viper {
+	values.mux.RLock()
+	defer values.mux.RUnlock()
+
+	return values.viper
+}
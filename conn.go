@@ -17,6 +17,16 @@ func (config *RedisConfig) Connect() (redis.Client, error) {
 	return redis.NewSynchClientWithSpec(spec)
 }
 
+// Subscribe subscribes the channel with a dedicated connection, that is closed when it fails or is unsubscribed
+func (config *RedisConfig) Subscribe(channel string) (<-chan []byte, func() error, error) {
+	subscription, err := newRedisSubscription(config, channel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return subscription.messages, subscription.unsubscribe, nil
+}
+
 // Connect ...
 func (config *DBConfig) Connect() (*sql.DB, error) {
 	return sql.Open(config.Driver, config.DataSource)
@@ -4,4 +4,6 @@ const (
 	background  = true
 	defaultPath = "."
 	path_key    = "path"
+
+	defaultEnvPrefix = "MANAGER"
 )
@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrComponentExists is returned when adding a component with a key already used by other component, of any kind
+var ErrComponentExists = errors.New("component key already in use")
+
+// ErrComponentNotFound is returned when removing a component with a key that isn't on the registry of its kind
+var ErrComponentNotFound = errors.New("component not found")
+
+// ErrManagerStarting is returned when adding a component while the manager is starting, as it wouldn't be started
+var ErrManagerStarting = errors.New("components can't be added while the manager is starting")
+
+// ComponentError ...
+type ComponentError struct {
+	Key    string
+	Kind   string
+	Action string
+	Err    error
+}
+
+// Error ...
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("error on %s [ %s: %s ]: %s", e.Action, e.Kind, e.Key, e.Err)
+}
+
+// Unwrap ...
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// ManagerError aggregates the errors of the components on a start or stop of the manager
+type ManagerError struct {
+	Action string
+	Errors []error
+}
+
+// Error ...
+func (e *ManagerError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("manager %s failed with %d error(s): %s", e.Action, len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap ...
+func (e *ManagerError) Unwrap() []error {
+	return e.Errors
+}
+
+// add ...
+func (e *ManagerError) add(errs ...error) {
+	for _, err := range errs {
+		if err != nil {
+			e.Errors = append(e.Errors, err)
+		}
+	}
+}
+
+// errorOrNil ...
+func (e *ManagerError) errorOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
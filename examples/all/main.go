@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -16,7 +17,7 @@ import (
 	"github.com/streadway/amqp"
 )
 
-var log = logger.NewLogDefault("manager", logger.InfoLevel)
+var log = logger.NewLogDefault("manager", logger.LevelInfo)
 
 func dummy_process() error {
 	log.Info("hello, i'm executing the dummy process")
@@ -145,7 +146,7 @@ func main() {
 	}
 	simpleWeb = m.GetWeb("web_echo")
 	simpleWeb.AddRoute(http.MethodGet, "/web_echo/:id", dummy_web_echo_handler)
-	go simpleWeb.Start() // starting this because of the gateway
+	go simpleWeb.Start(context.Background()) // starting this because of the gateway
 
 	log.Info("waiting 1 seconds...")
 	<-time.After(time.Duration(1) * time.Second)
@@ -186,26 +187,34 @@ func main() {
 	//
 	// manager: workqueue
 	workqueueConfig := manager.NewWorkListConfig("queue_001", 1, 2, time.Second*2, manager.FIFO)
-	workqueue := m.NewSimpleWorkList(workqueueConfig, work_handler, nil, nil)
+	workqueue, err := m.NewSimpleWorkList(workqueueConfig, work_handler, nil, nil)
+	if err != nil {
+		log.Errorf("%s", err)
+		return
+	}
 	m.AddWorkList("queue_001", workqueue)
 	workqueue = m.GetWorkList("queue_001")
 	for i := 1; i <= 1000; i++ {
 		workqueue.AddWork(fmt.Sprintf("PROCESS: %d", i), fmt.Sprintf("THIS IS MY MESSAGE %d", i))
 	}
-	if err := workqueue.Start(); err != nil {
+	if err := workqueue.Start(context.Background()); err != nil {
 		log.Errorf("MAIN: error on workqueue %s", err)
 	}
 
 	//
 	// manager: bulk workqueue
 	bulkWorkqueueConfig := manager.NewBulkWorkListConfig("bulk_queue_001", 10, 1, 2, time.Second*2, manager.FIFO)
-	bulkWorkqueue := m.NewSimpleBulkWorkList(bulkWorkqueueConfig, bulk_work_handler, bulkWorkRecoverHandler, bulkWorkRecoverWastedRetriesHandler)
+	bulkWorkqueue, err := m.NewSimpleBulkWorkList(bulkWorkqueueConfig, bulk_work_handler, bulkWorkRecoverHandler, bulkWorkRecoverWastedRetriesHandler)
+	if err != nil {
+		log.Errorf("%s", err)
+		return
+	}
 	m.AddWorkList("bulk_queue_001", bulkWorkqueue)
 	workqueue = m.GetWorkList("bulk_queue_001")
 	for i := 1; i <= 1000; i++ {
 		workqueue.AddWork(fmt.Sprintf("PROCESS: %d", i), fmt.Sprintf("THIS IS MY MESSAGE %d", i))
 	}
-	if err := workqueue.Start(); err != nil {
+	if err := workqueue.Start(context.Background()); err != nil {
 		log.Errorf("MAIN: error on bulk workqueue %s", err)
 	}
 
@@ -224,7 +233,7 @@ func main() {
 		log.Errorf("%s", err)
 	}
 
-	if err := rabbitmqProducer.Start(); err != nil {
+	if err := rabbitmqProducer.Start(context.Background()); err != nil {
 		log.Errorf("%s", err)
 	}
 	m.AddRabbitmqProducer("rabbitmq_producer", rabbitmqProducer)
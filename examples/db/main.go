@@ -5,7 +5,7 @@ import (
 	"github.com/joaosoft/manager"
 )
 
-var log = logger.NewLogDefault("manager", logger.InfoLevel)
+var log = logger.NewLogDefault("manager", logger.LevelInfo)
 
 func main() {
 	//
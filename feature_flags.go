@@ -0,0 +1,379 @@
+package manager
+
+import (
+	"context"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+// defaultFeatureFlagsSection is the key of the flags on the config
+const defaultFeatureFlagsSection = "feature_flags"
+
+// reasons of the flag evaluations
+const (
+	FlagReasonNotFound = "not_found"
+	FlagReasonDisabled = "disabled"
+	FlagReasonDenied   = "denied"
+	FlagReasonAllowed  = "allowed"
+	FlagReasonRollout  = "rollout"
+	FlagReasonEnabled  = "enabled"
+)
+
+// actions of the flag changes
+const (
+	FlagChangeCreated = "created"
+	FlagChangeUpdated = "updated"
+	FlagChangeDeleted = "deleted"
+)
+
+// FeatureFlag ...
+type FeatureFlag struct {
+	Enabled bool `json:"enabled"`
+	// Rollout is the percentage of the users (or tenants) with the flag enabled, all of them when it isn't given
+	Rollout      *float64 `json:"rollout,omitempty" validate:"min=0,max=100"`
+	AllowUsers   []string `json:"allow_users,omitempty"`
+	AllowTenants []string `json:"allow_tenants,omitempty"`
+	DenyUsers    []string `json:"deny_users,omitempty"`
+	DenyTenants  []string `json:"deny_tenants,omitempty"`
+	// Variants are the weights of the variants of the enabled flag
+	Variants       map[string]int `json:"variants,omitempty"`
+	DefaultVariant string         `json:"default_variant,omitempty"`
+}
+
+// FlagContext identifies who the flags are evaluated for, the rollouts and variants are by user, or by tenant without user
+type FlagContext struct {
+	UserID   string `json:"user_id,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// FlagEvaluation ...
+type FlagEvaluation struct {
+	Key     string `json:"key"`
+	Enabled bool   `json:"enabled"`
+	Variant string `json:"variant,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// FlagChange is an entry of the audit log of the flags
+type FlagChange struct {
+	Timestamp time.Time    `json:"timestamp"`
+	Key       string       `json:"key"`
+	Action    string       `json:"action"`
+	Old       *FeatureFlag `json:"old,omitempty"`
+	New       *FeatureFlag `json:"new,omitempty"`
+}
+
+// FeatureFlagsOption ...
+type FeatureFlagsOption func(flags *FeatureFlags)
+
+// Reconfigure ...
+func (flags *FeatureFlags) Reconfigure(options ...FeatureFlagsOption) {
+	for _, option := range options {
+		option(flags)
+	}
+}
+
+// WithFlagsSection sets the key of the flags on the config, feature_flags by default
+func WithFlagsSection(section string) FeatureFlagsOption {
+	return func(flags *FeatureFlags) {
+		flags.section = section
+	}
+}
+
+// WithFlagsAuditSize sets the number of changes kept on the audit log, 100 by default
+func WithFlagsAuditSize(size int) FeatureFlagsOption {
+	return func(flags *FeatureFlags) {
+		flags.auditSize = size
+	}
+}
+
+// WithFlagsAuditHandler adds a handler called with each change of the flags, to keep the audit log elsewhere
+func WithFlagsAuditHandler(handler func(change *FlagChange)) FeatureFlagsOption {
+	return func(flags *FeatureFlags) {
+		flags.auditHandlers = append(flags.auditHandlers, handler)
+	}
+}
+
+// FeatureFlags evaluates the flags of a config section, that are reloaded when the config changes
+type FeatureFlags struct {
+	config        IConfig
+	section       string
+	flags         map[string]*FeatureFlag
+	loaded        bool
+	audit         []*FlagChange
+	auditSize     int
+	auditHandlers []func(change *FlagChange)
+	logger        logger.ILogger
+	state         *StateMachine
+	mux           sync.RWMutex
+	reloadMux     sync.Mutex
+}
+
+// NewFeatureFlags loads the flags from the config, and subscribes its changes
+func (manager *Manager) NewFeatureFlags(config IConfig, options ...FeatureFlagsOption) (*FeatureFlags, error) {
+	flags := &FeatureFlags{
+		config:    config,
+		section:   defaultFeatureFlagsSection,
+		flags:     make(map[string]*FeatureFlag),
+		audit:     make([]*FlagChange, 0),
+		auditSize: 100,
+		logger:    manager.logger,
+		state:     NewStateMachine(),
+	}
+	flags.Reconfigure(options...)
+
+	if err := flags.Reload(); err != nil {
+		return nil, err
+	}
+
+	// the flags are reloaded on the changes of the configs that notify them
+	if notifier, ok := config.(IConfigNotifier); ok {
+		notifier.OnChange(func(diff *ConfigDiff) {
+			if !diff.Has(flags.section) {
+				return
+			}
+
+			if err := flags.Reload(); err != nil {
+				flags.logger.Errorf("error reloading the feature flags, keeping the current flags [ section: %s ]: %s", flags.section, err)
+			}
+		})
+	}
+
+	return flags, nil
+}
+
+// Start ...
+func (flags *FeatureFlags) Start(ctx context.Context) error {
+	if flags.state.start() {
+		flags.state.started(nil)
+	}
+	return nil
+}
+
+// Stop ...
+func (flags *FeatureFlags) Stop(ctx context.Context) error {
+	if flags.state.stop() {
+		flags.state.stopped(nil)
+	}
+	return nil
+}
+
+// Started ...
+func (flags *FeatureFlags) Started() bool {
+	return flags.state.Started()
+}
+
+// Reload loads the flags from the config, recording the changes on the audit log
+func (flags *FeatureFlags) Reload() error {
+	flags.reloadMux.Lock()
+	defer flags.reloadMux.Unlock()
+
+	loaded := make(map[string]*FeatureFlag)
+	if configIsSet(flags.config, flags.section) {
+		if err := configUnmarshalKey(flags.config, flags.section, &loaded); err != nil {
+			return err
+		}
+	}
+
+	flags.mux.Lock()
+	previous := flags.flags
+	initial := !flags.loaded
+	flags.flags = loaded
+	flags.loaded = true
+	flags.mux.Unlock()
+
+	if initial {
+		return nil
+	}
+
+	keys := make(map[string]bool)
+	for key := range previous {
+		keys[key] = true
+	}
+	for key := range loaded {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	now := time.Now()
+	for _, key := range sorted {
+		old, current := previous[key], loaded[key]
+
+		change := &FlagChange{Timestamp: now, Key: key, Old: old, New: current}
+		switch {
+		case old == nil:
+			change.Action = FlagChangeCreated
+		case current == nil:
+			change.Action = FlagChangeDeleted
+		case !reflect.DeepEqual(old, current):
+			change.Action = FlagChangeUpdated
+		default:
+			continue
+		}
+
+		flags.record(change)
+	}
+
+	return nil
+}
+
+// record adds the change to the audit log
+func (flags *FeatureFlags) record(change *FlagChange) {
+	flags.logger.Infof("feature flag %s [ key: %s ]", change.Action, change.Key)
+
+	flags.mux.Lock()
+	flags.audit = append(flags.audit, change)
+	if flags.auditSize > 0 && len(flags.audit) > flags.auditSize {
+		flags.audit = flags.audit[len(flags.audit)-flags.auditSize:]
+	}
+	handlers := flags.auditHandlers
+	flags.mux.Unlock()
+
+	for _, handler := range handlers {
+		handler(change)
+	}
+}
+
+// AuditLog returns the last changes of the flags
+func (flags *FeatureFlags) AuditLog() []*FlagChange {
+	flags.mux.RLock()
+	defer flags.mux.RUnlock()
+
+	return append([]*FlagChange{}, flags.audit...)
+}
+
+// Flags returns the flags by key
+func (flags *FeatureFlags) Flags() map[string]*FeatureFlag {
+	flags.mux.RLock()
+	defer flags.mux.RUnlock()
+
+	result := make(map[string]*FeatureFlag, len(flags.flags))
+	for key, flag := range flags.flags {
+		result[key] = flag
+	}
+
+	return result
+}
+
+// IsEnabled ...
+func (flags *FeatureFlags) IsEnabled(key string, flagContext FlagContext) bool {
+	return flags.Evaluate(key, flagContext).Enabled
+}
+
+// Variant gets the variant of the flag, the default variant when the flag isn't enabled
+func (flags *FeatureFlags) Variant(key string, flagContext FlagContext) string {
+	return flags.Evaluate(key, flagContext).Variant
+}
+
+// Evaluate evaluates the flag for the context. the deny lists have precedence over the allow lists,
+// that have precedence over the rollout. the rollout and the variants are chosen by hashing the key
+// of the flag with the id of the user (or tenant), so that the same id always gets the same result
+func (flags *FeatureFlags) Evaluate(key string, flagContext FlagContext) *FlagEvaluation {
+	flags.mux.RLock()
+	flag, exists := flags.flags[key]
+	flags.mux.RUnlock()
+
+	evaluation := &FlagEvaluation{Key: key}
+	if !exists {
+		evaluation.Reason = FlagReasonNotFound
+		return evaluation
+	}
+	evaluation.Variant = flag.DefaultVariant
+
+	switch {
+	case !flag.Enabled:
+		evaluation.Reason = FlagReasonDisabled
+	case contains(flag.DenyUsers, flagContext.UserID) || contains(flag.DenyTenants, flagContext.TenantID):
+		evaluation.Reason = FlagReasonDenied
+	case contains(flag.AllowUsers, flagContext.UserID) || contains(flag.AllowTenants, flagContext.TenantID):
+		evaluation.Enabled = true
+		evaluation.Reason = FlagReasonAllowed
+	case flag.Rollout == nil || *flag.Rollout >= 100:
+		evaluation.Enabled = true
+		evaluation.Reason = FlagReasonEnabled
+	default:
+		evaluation.Reason = FlagReasonRollout
+		if id := flagContext.id(); id != "" {
+			evaluation.Enabled = float64(flagBucket(key, id, 10000))/100 < *flag.Rollout
+		}
+	}
+
+	if evaluation.Enabled {
+		if variant := flag.variant(key, flagContext.id()); variant != "" {
+			evaluation.Variant = variant
+		}
+	}
+
+	return evaluation
+}
+
+// variant chooses the variant by its weight, the default variant is used without id
+func (flag *FeatureFlag) variant(key, id string) string {
+	if id == "" || len(flag.Variants) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(flag.Variants))
+	total := 0
+	for name, weight := range flag.Variants {
+		if weight > 0 {
+			names = append(names, name)
+			total += weight
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	bucket := int(flagBucket(key+":variant", id, uint32(total)))
+	for _, name := range names {
+		bucket -= flag.Variants[name]
+		if bucket < 0 {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// id ...
+func (flagContext FlagContext) id() string {
+	if flagContext.UserID != "" {
+		return flagContext.UserID
+	}
+	return flagContext.TenantID
+}
+
+// flagBucket hashes the key of the flag with the id into one of the buckets
+func flagBucket(key, id string, buckets uint32) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(key + ":" + id))
+
+	return hash.Sum32() % buckets
+}
+
+// contains ...
+func contains(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,145 @@
+package manager
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestFeatureFlags(t *testing.T, content string) (*FeatureFlags, string) {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("error writing the config file: %s", err)
+	}
+
+	m := NewManager()
+	config, err := m.NewSimpleConfig(file, nil)
+	if err != nil {
+		t.Fatalf("error loading the config: %s", err)
+	}
+
+	flags, err := m.NewFeatureFlags(config)
+	if err != nil {
+		t.Fatalf("error loading the feature flags: %s", err)
+	}
+
+	return flags, file
+}
+
+func TestFeatureFlagsEvaluate(t *testing.T) {
+	flags, _ := newTestFeatureFlags(t, `{"feature_flags": {
+		"disabled": {"enabled": false, "allow_users": ["alice"]},
+		"enabled": {"enabled": true},
+		"nobody": {"enabled": true, "rollout": 0, "allow_users": ["alice", "bob"], "allow_tenants": ["acme"], "deny_users": ["bob"], "deny_tenants": ["evil"]},
+		"everybody": {"enabled": true, "rollout": 100, "default_variant": "control", "variants": {"blue": 1, "green": 0}}
+	}}`)
+
+	tests := []struct {
+		name            string
+		key             string
+		flagContext     FlagContext
+		expectedEnabled bool
+		expectedReason  string
+		expectedVariant string
+	}{
+		{name: "not found", key: "missing", flagContext: FlagContext{UserID: "alice"}, expectedReason: FlagReasonNotFound},
+		{name: "disabled over allowed", key: "disabled", flagContext: FlagContext{UserID: "alice"}, expectedReason: FlagReasonDisabled},
+		{name: "enabled without rollout", key: "enabled", expectedEnabled: true, expectedReason: FlagReasonEnabled},
+		{name: "denied user over allowed user", key: "nobody", flagContext: FlagContext{UserID: "bob"}, expectedReason: FlagReasonDenied},
+		{name: "denied tenant over allowed user", key: "nobody", flagContext: FlagContext{UserID: "alice", TenantID: "evil"}, expectedReason: FlagReasonDenied},
+		{name: "allowed user over rollout", key: "nobody", flagContext: FlagContext{UserID: "alice"}, expectedEnabled: true, expectedReason: FlagReasonAllowed},
+		{name: "allowed tenant over rollout", key: "nobody", flagContext: FlagContext{UserID: "carol", TenantID: "acme"}, expectedEnabled: true, expectedReason: FlagReasonAllowed},
+		{name: "out of the rollout", key: "nobody", flagContext: FlagContext{UserID: "carol"}, expectedReason: FlagReasonRollout},
+		{name: "variant by weight", key: "everybody", flagContext: FlagContext{UserID: "carol"}, expectedEnabled: true, expectedReason: FlagReasonEnabled, expectedVariant: "blue"},
+		{name: "default variant without id", key: "everybody", expectedEnabled: true, expectedReason: FlagReasonEnabled, expectedVariant: "control"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evaluation := flags.Evaluate(test.key, test.flagContext)
+
+			if evaluation.Enabled != test.expectedEnabled || evaluation.Reason != test.expectedReason || evaluation.Variant != test.expectedVariant {
+				t.Errorf("the evaluation is %+v, expected enabled %t, reason %s and variant %q",
+					evaluation, test.expectedEnabled, test.expectedReason, test.expectedVariant)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagsRolloutBuckets(t *testing.T) {
+	flags, _ := newTestFeatureFlags(t, `{"feature_flags": {"half": {"enabled": true, "rollout": 50}}}`)
+
+	// the bucket is the fnv-1a hash of the key with the id, so it doesn't change between versions or instances
+	hash := fnv.New32a()
+	hash.Write([]byte("half:user-1"))
+	if bucket := flagBucket("half", "user-1", 10000); bucket != hash.Sum32()%10000 {
+		t.Errorf("the bucket is %d, expected %d", bucket, hash.Sum32()%10000)
+	}
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		flagContext := FlagContext{UserID: fmt.Sprintf("user-%d", i)}
+
+		evaluation := flags.Evaluate("half", flagContext)
+		for j := 0; j < 3; j++ {
+			if again := flags.Evaluate("half", flagContext); again.Enabled != evaluation.Enabled {
+				t.Fatalf("the evaluation of the user %s changed", flagContext.UserID)
+			}
+		}
+
+		if evaluation.Enabled {
+			enabled++
+		}
+	}
+
+	if enabled < 400 || enabled > 600 {
+		t.Errorf("the rollout of 50%% enabled the flag for %d of 1000 users", enabled)
+	}
+
+	// the tenant is used without user
+	byTenant := flags.Evaluate("half", FlagContext{TenantID: "user-1"})
+	byUser := flags.Evaluate("half", FlagContext{UserID: "user-1"})
+	if byTenant.Enabled != byUser.Enabled {
+		t.Errorf("the evaluation by the tenant user-1 is %t, expected the one of the user user-1 %t", byTenant.Enabled, byUser.Enabled)
+	}
+}
+
+func TestFeatureFlagsReloadAudit(t *testing.T) {
+	flags, file := newTestFeatureFlags(t, `{"feature_flags": {"kept": {"enabled": true}, "updated": {"enabled": false}, "deleted": {"enabled": true}}}`)
+
+	if err := os.WriteFile(file, []byte(`{"feature_flags": {"kept": {"enabled": true}, "updated": {"enabled": true}, "created": {"enabled": true}}}`), 0644); err != nil {
+		t.Fatalf("error writing the config file: %s", err)
+	}
+
+	if err := flags.config.Reload(); err != nil {
+		t.Fatalf("error reloading the config: %s", err)
+	}
+
+	expected := []struct {
+		key    string
+		action string
+	}{
+		{key: "created", action: FlagChangeCreated},
+		{key: "deleted", action: FlagChangeDeleted},
+		{key: "updated", action: FlagChangeUpdated},
+	}
+
+	audit := flags.AuditLog()
+	if len(audit) != len(expected) {
+		t.Fatalf("the audit log has %d changes, expected %d", len(audit), len(expected))
+	}
+
+	for i, change := range audit {
+		if change.Key != expected[i].key || change.Action != expected[i].action {
+			t.Errorf("the change %d is %s of %s, expected %s of %s", i, change.Action, change.Key, expected[i].action, expected[i].key)
+		}
+	}
+
+	if !flags.IsEnabled("updated", FlagContext{}) || flags.IsEnabled("deleted", FlagContext{}) {
+		t.Errorf("the flags weren't reloaded with the config")
+	}
+}
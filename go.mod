@@ -4,6 +4,7 @@ go 1.20
 
 require (
 	github.com/alphazero/Go-Redis v0.0.0-20120924171622-a0637b154364
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/go-sql-driver/mysql v1.7.1
 	github.com/joaosoft/logger v0.0.0-20240321164508-fe379344de3b
 	github.com/joaosoft/web v0.0.0-20240321165800-ab860bfd0ff2
@@ -11,13 +12,13 @@ require (
 	github.com/labstack/gommon v0.4.0
 	github.com/lib/pq v1.10.9
 	github.com/nsqio/go-nsq v1.1.0
+	github.com/pelletier/go-toml/v2 v2.0.8
 	github.com/spf13/viper v1.16.0
 	github.com/streadway/amqp v1.0.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
-	github.com/fsnotify/fsnotify v1.6.0 // indirect
 	github.com/golang/snappy v0.0.1 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/joaosoft/auth-types/basic v0.0.0-20230531143726-6905d84fa794 // indirect
@@ -29,7 +30,6 @@ require (
 	github.com/mattn/go-colorable v0.1.12 // indirect
 	github.com/mattn/go-isatty v0.0.14 // indirect
 	github.com/mitchellh/mapstructure v1.5.0 // indirect
-	github.com/pelletier/go-toml/v2 v2.0.8 // indirect
 	github.com/satori/go.uuid v1.2.0 // indirect
 	github.com/spf13/afero v1.9.5 // indirect
 	github.com/spf13/cast v1.5.1 // indirect
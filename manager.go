@@ -1,10 +1,12 @@
 package manager
 
 import (
+	"context"
+	"net/http"
 	"os"
 	"os/signal"
-	"reflect"
 	"syscall"
+	"time"
 
 	"sync"
 
@@ -13,29 +15,45 @@ import (
 
 // Manager ...
 type Manager struct {
-	processes         map[string]IProcess
-	configs           map[string]IConfig
-	redis             map[string]IRedis
-	nsqProducers      map[string]INSQProducer
-	nsqConsumers      map[string]INSQConsumer
-	rabbitmqProducers map[string]IRabbitmqProducer
-	rabbitmqConsumers map[string]IRabbitmqConsumer
-	dbs               map[string]IDB
-	webs              map[string]IWeb
-	gateways          map[string]IGateway
-	worklist          map[string]IWorkList
-	runInBackground   bool
-	config            *ManagerConfig
-	logger            logger.ILogger
-	isLogExternal     bool
-
-	quit    chan int
-	started bool
+	processes          map[string]IProcess
+	configs            map[string]IConfig
+	redis              map[string]IRedis
+	nsqProducers       map[string]INSQProducer
+	nsqConsumers       map[string]INSQConsumer
+	rabbitmqProducers  map[string]IRabbitmqProducer
+	rabbitmqConsumers  map[string]IRabbitmqConsumer
+	dbs                map[string]IDB
+	webs               map[string]IWeb
+	gateways           map[string]IGateway
+	worklist           map[string]IWorkList
+	featureFlags       map[string]IFeatureFlags
+	components         map[string]*componentConfig
+	registryMux        sync.RWMutex
+	runInBackground    bool
+	shutdownTimeout    time.Duration
+	healthCheckTimeout time.Duration
+	adminAddress       string
+	adminWeb           string
+	adminMounted       bool
+	adminServer        *http.Server
+	config             *ManagerConfig
+	appConfig          IConfig
+	handlers           *HandlerRegistry
+	logger             logger.ILogger
+	isLogExternal      bool
+
+	supervisors    map[string]*supervisor
+	supervisorsMux sync.Mutex
+	escalation     chan error
+
+	quit  chan int
+	done  chan struct{}
+	state *StateMachine
 }
 
 // NewManager ...
 func NewManager(options ...ManagerOption) *Manager {
-	config, _, err := NewConfig()
+	config, appConfig, err := NewConfig()
 	log := logger.NewLogDefault("manager", logger.LevelWarn)
 
 	service := &Manager{
@@ -50,14 +68,23 @@ func NewManager(options ...ManagerOption) *Manager {
 		webs:              make(map[string]IWeb),
 		gateways:          make(map[string]IGateway),
 		worklist:          make(map[string]IWorkList),
+		featureFlags:      make(map[string]IFeatureFlags),
+		components:        make(map[string]*componentConfig),
+		supervisors:       make(map[string]*supervisor),
+		escalation:        make(chan error, 1),
 		quit:              make(chan int),
+		state:             NewStateMachine(),
 		logger:            log,
 		config:            config.Manager,
 	}
 
 	if err != nil {
 		service.logger.Error(err.Error())
-	} else if config.Manager != nil {
+	} else {
+		service.appConfig = appConfig
+	}
+
+	if err == nil && config.Manager != nil {
 		level, _ := logger.ParseLevel(config.Manager.Log.Level)
 		service.logger.Debugf("setting log level to %s", level)
 		service.logger.Reconfigure(logger.WithLevel(level))
@@ -70,138 +97,169 @@ func NewManager(options ...ManagerOption) *Manager {
 
 // Started ...
 func (manager *Manager) Started() bool {
-	return manager.started
+	return manager.state.State() == StateRunning
 }
 
-// Start ...
+// Start starts all the components, when any of them fails the ones already started are stopped
+// and the errors are returned aggregated in a *ManagerError
 func (manager *Manager) Start() error {
-	if !manager.started {
-		manager.started = true
-		c := make(chan bool, 1)
-		if manager.runInBackground {
-			go manager.executeStart(c)
-			<-c
-		} else {
-			return manager.executeStart(c)
+	if err := manager.state.Transition(StateStarting, nil); err != nil {
+		if state := manager.state.State(); state == StateStarting || state == StateRunning {
+			return nil
 		}
+		return err
 	}
 
-	return nil
-}
+	// discard an escalation left by a previous run
+	select {
+	case <-manager.escalation:
+	default:
+	}
 
-// Stop ...
-func (manager *Manager) Stop() error {
-	if manager.started {
-		manager.started = false
-		c := make(chan bool)
-		if manager.runInBackground {
-			go manager.executeStop(c)
-			<-c
-		} else {
-			return manager.executeStop(c)
-		}
+	if err := manager.startAdmin(); err != nil {
+		manager.logger.Error(err)
+		manager.state.Transition(StateFailed, err)
+		return err
+	}
+
+	if err := manager.executeStart(context.Background()); err != nil {
+		manager.stopAdmin(context.Background())
+		manager.state.Transition(StateFailed, err)
+		return err
+	}
 
+	done := make(chan struct{})
+	manager.done = done
+	manager.state.Transition(StateRunning, nil)
+
+	if manager.runInBackground {
+		go manager.wait(done)
 		return nil
 	}
 
-	return nil
+	return manager.wait(done)
 }
 
-func (manager *Manager) executeStart(c chan bool) error {
-	manager.logger.Info("starting...")
-
-	// listen for termination signals
-	termChan := make(chan os.Signal, 1)
+// Stop stops all the components, returning the errors aggregated in a *ManagerError
+func (manager *Manager) Stop() error {
+	if manager.state.State() != StateRunning {
+		return nil
+	}
 
-	if !manager.runInBackground {
-		signal.Notify(termChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
+	// only one of the concurrent calls gets to stop
+	if err := manager.state.Transition(StateStopping, nil); err != nil {
+		return nil
 	}
 
-	var wg sync.WaitGroup
+	close(manager.done)
 
-	if err := manager.executeAction("start", manager.dbs, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.nsqProducers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.nsqConsumers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.rabbitmqProducers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.rabbitmqConsumers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.redis, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.worklist, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.processes, &wg); err != nil {
-		return err
+	ctx, cancel := manager.shutdownContext()
+	defer cancel()
+
+	err := manager.executeStop(ctx)
+	if adminErr := manager.stopAdmin(ctx); adminErr != nil {
+		manager.logger.Errorf("error stopping admin server: %s", adminErr)
 	}
-	if err := manager.executeAction("start", manager.webs, &wg); err != nil {
-		return err
+
+	if err != nil {
+		manager.state.Transition(StateFailed, err)
+	} else {
+		manager.state.Transition(StateStopped, nil)
 	}
 
-	wg.Wait()
+	return err
+}
 
-	if manager.runInBackground {
-		c <- true
-	}
+func (manager *Manager) wait(done chan struct{}) error {
+	// listen for termination signals
+	termChan := make(chan os.Signal, 1)
 
-	manager.logger.Infof("started")
+	if !manager.runInBackground {
+		signal.Notify(termChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
+		defer signal.Stop(termChan)
+	}
 
 	select {
 	case <-termChan:
 		manager.logger.Infof("received term signal")
 	case <-manager.quit:
 		manager.logger.Infof("received shutdown signal")
+	case <-done:
+		return nil
+	case err := <-manager.escalation:
+		managerErr := &ManagerError{Action: "supervise"}
+		managerErr.add(err, manager.Stop())
+		return managerErr
 	}
 
 	return manager.Stop()
 }
 
-func (manager *Manager) executeStop(c chan bool) error {
-	manager.logger.Info("stopping...")
-
-	var wg sync.WaitGroup
+func (manager *Manager) executeStart(ctx context.Context) error {
+	manager.logger.Info("starting...")
 
-	if err := manager.executeAction("stop", manager.processes, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.worklist, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.webs, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.nsqProducers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.nsqConsumers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.rabbitmqProducers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.rabbitmqConsumers, &wg); err != nil {
+	g, err := manager.buildGraph()
+	if err != nil {
+		manager.logger.Error(err)
 		return err
 	}
-	if err := manager.executeAction("stop", manager.redis, &wg); err != nil {
-		return err
+
+	var mux sync.Mutex
+	started := make(map[*node]bool)
+
+	errs := g.run(false, func(n *node) error {
+		if err := manager.startComponent(ctx, n); err != nil {
+			return err
+		}
+
+		mux.Lock()
+		started[n] = true
+		mux.Unlock()
+
+		return nil
+	})
+
+	if len(errs) > 0 {
+		managerErr := &ManagerError{Action: "start"}
+		managerErr.add(errs...)
+
+		manager.logger.Warn("rolling back the started components...")
+		stopCtx, cancel := manager.shutdownContext()
+		defer cancel()
+
+		managerErr.add(g.run(true, func(n *node) error {
+			if !started[n] {
+				return nil
+			}
+			return manager.stopComponent(stopCtx, n)
+		})...)
+
+		manager.logger.Error(managerErr)
+		return managerErr
 	}
-	if err := manager.executeAction("stop", manager.dbs, &wg); err != nil {
+
+	manager.logger.Infof("started")
+
+	return nil
+}
+
+func (manager *Manager) executeStop(ctx context.Context) error {
+	manager.logger.Info("stopping...")
+
+	g, err := manager.buildGraph()
+	if err != nil {
+		manager.logger.Error(err)
 		return err
 	}
 
-	wg.Wait()
+	managerErr := &ManagerError{Action: "stop"}
+	managerErr.add(g.run(true, func(n *node) error {
+		return manager.stopComponent(ctx, n)
+	})...)
 
-	if manager.runInBackground {
-		c <- true
+	if err := managerErr.errorOrNil(); err != nil {
+		manager.logger.Error(err)
+		return err
 	}
 
 	manager.logger.Infof("stopped")
@@ -209,35 +267,55 @@ func (manager *Manager) executeStop(c chan bool) error {
 	return nil
 }
 
-func (manager *Manager) executeAction(action string, obj interface{}, wg *sync.WaitGroup) error {
-	wg.Add(1)
-	defer wg.Done()
-
-	objMap := reflect.ValueOf(obj)
-
-	if objMap.Kind() == reflect.Map {
-		var wgProcess sync.WaitGroup
-		for _, key := range objMap.MapKeys() {
-			value := objMap.MapIndex(key)
-
-			started := reflect.ValueOf(value.Interface()).MethodByName("Started").Call([]reflect.Value{})[0]
-			switch action {
-			case "start":
-				if !started.Bool() {
-					wgProcess.Add(1)
-					go reflect.ValueOf(value.Interface()).MethodByName("Start").Call([]reflect.Value{reflect.ValueOf(&wgProcess)})
-					manager.logger.Infof("started [ process: %s ]", key)
-				}
-			case "stop":
-				if started.Bool() {
-					wgProcess.Add(1)
-					go reflect.ValueOf(value.Interface()).MethodByName("Stop").Call([]reflect.Value{reflect.ValueOf(&wgProcess)})
-					manager.logger.Infof("stopped [ process: %s ]", key)
-				}
+// executeAction starts or stops the component, following the transitions of its state
+func (manager *Manager) executeAction(ctx context.Context, action string, n *node) error {
+	state := n.config.state
+
+	var err error
+	var done string
+
+	switch action {
+	case "start":
+		if transitionErr := state.Transition(StateStarting, nil); transitionErr != nil {
+			if current := state.State(); current == StateStarting || current == StateRunning {
+				return nil
 			}
+			return &ComponentError{Key: n.key, Kind: n.kind, Action: action, Err: transitionErr}
 		}
-		wgProcess.Wait()
+
+		if n.component.Started() {
+			return state.Transition(StateRunning, nil)
+		}
+		err, done = n.component.Start(ctx), "started"
+	case "stop":
+		// only the running (or failed) components can be stopped
+		if state.Transition(StateStopping, nil) != nil {
+			return nil
+		}
+
+		if !n.component.Started() {
+			return state.Transition(StateStopped, nil)
+		}
+		err, done = n.component.Stop(ctx), "stopped"
+	default:
+		return nil
 	}
 
+	if err != nil {
+		state.Transition(StateFailed, err)
+
+		componentErr := &ComponentError{Key: n.key, Kind: n.kind, Action: action, Err: err}
+		manager.logger.Error(componentErr)
+		return componentErr
+	}
+
+	if action == "start" {
+		state.Transition(StateRunning, nil)
+	} else {
+		state.Transition(StateStopped, nil)
+	}
+
+	manager.logger.Infof("%s [ %s: %s ]", done, n.kind, n.key)
+
 	return nil
 }
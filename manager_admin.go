@@ -0,0 +1,152 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/joaosoft/web"
+	"github.com/labstack/echo"
+)
+
+const (
+	adminPathHealth = "/healthz"
+	adminPathReady  = "/readyz"
+	adminPathStatus = "/status"
+)
+
+// adminEndpoint ...
+type adminEndpoint func(ctx context.Context) (int, interface{})
+
+// adminEndpoints ...
+func (manager *Manager) adminEndpoints() map[string]adminEndpoint {
+	return map[string]adminEndpoint{
+		adminPathHealth: func(ctx context.Context) (int, interface{}) {
+			report := manager.Health(ctx)
+			if !report.Live {
+				return http.StatusServiceUnavailable, report
+			}
+			return http.StatusOK, report
+		},
+		adminPathReady: func(ctx context.Context) (int, interface{}) {
+			report := manager.Health(ctx)
+			if !report.Ready {
+				return http.StatusServiceUnavailable, report
+			}
+			return http.StatusOK, report
+		},
+		adminPathStatus: func(ctx context.Context) (int, interface{}) {
+			return http.StatusOK, manager.Status()
+		},
+	}
+}
+
+// AdminHandler returns the handler with the /healthz, /readyz and /status endpoints
+func (manager *Manager) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	for path, endpoint := range manager.adminEndpoints() {
+		mux.HandleFunc(path, httpAdminHandler(endpoint))
+	}
+
+	return mux
+}
+
+// MountAdminEndpoints adds the /healthz, /readyz and /status endpoints, under the prefix, to the web server
+func (manager *Manager) MountAdminEndpoints(w IWeb, prefix string) error {
+	for path, endpoint := range manager.adminEndpoints() {
+		endpoint := endpoint
+
+		var handler HandlerFunc
+		switch w.GetClient().(type) {
+		case *http.Server:
+			handler = httpAdminHandler(endpoint)
+		case *echo.Echo:
+			handler = func(ctx echo.Context) error {
+				status, body := endpoint(ctx.Request().Context())
+				return ctx.JSON(status, body)
+			}
+		case *web.Server:
+			handler = func(ctx *web.Context) error {
+				// the web server doesn't give the request, nor its context, to the handlers, so the endpoint
+				// isn't canceled when the client goes away, it is only bounded by the health check timeout
+				requestCtx, cancel := context.WithTimeout(context.Background(), manager.checkTimeout())
+				defer cancel()
+
+				status, body := endpoint(requestCtx)
+				return ctx.Response.JSON(web.Status(status), body)
+			}
+		default:
+			return fmt.Errorf("admin endpoints not supported on web client %T", w.GetClient())
+		}
+
+		if err := w.AddRoute(http.MethodGet, prefix+path, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startAdmin starts the admin listener and mounts the admin endpoints on the configured web
+func (manager *Manager) startAdmin() error {
+	if manager.adminWeb != "" {
+		manager.registryMux.RLock()
+		w, exists := manager.webs[manager.adminWeb]
+		manager.registryMux.RUnlock()
+
+		if !exists {
+			return fmt.Errorf("admin web %s doesn't exist", manager.adminWeb)
+		}
+
+		if !manager.adminMounted {
+			if err := manager.MountAdminEndpoints(w, ""); err != nil {
+				return err
+			}
+			manager.adminMounted = true
+		}
+	}
+
+	if manager.adminAddress == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", manager.adminAddress)
+	if err != nil {
+		return err
+	}
+
+	manager.adminServer = &http.Server{Handler: manager.AdminHandler()}
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			manager.logger.Errorf("admin server error [ address: %s ]: %s", manager.adminAddress, err)
+		}
+	}(manager.adminServer)
+
+	manager.logger.Infof("admin server listening [ address: %s ]", manager.adminAddress)
+
+	return nil
+}
+
+// stopAdmin ...
+func (manager *Manager) stopAdmin(ctx context.Context) error {
+	if manager.adminServer == nil {
+		return nil
+	}
+
+	server := manager.adminServer
+	manager.adminServer = nil
+
+	return server.Shutdown(ctx)
+}
+
+func httpAdminHandler(endpoint adminEndpoint) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, body := endpoint(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	}
+}
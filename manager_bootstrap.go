@@ -0,0 +1,121 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/joaosoft/logger"
+)
+
+// NewManagerFromConfig creates a manager with all the components declared on the configuration,
+// binding their handlers by name from the registry. when the configuration is nil,
+// the manager configuration of the application config file is used
+func NewManagerFromConfig(config *ManagerConfig, handlers *HandlerRegistry, options ...ManagerOption) (*Manager, error) {
+	manager := NewManager()
+	manager.handlers = handlers
+
+	if config != nil {
+		manager.config = config
+		if level, err := logger.ParseLevel(config.Log.Level); err == nil && config.Log.Level != "" {
+			manager.logger.Reconfigure(logger.WithLevel(level))
+		}
+	}
+	manager.Reconfigure(options...)
+
+	if manager.config == nil {
+		return nil, fmt.Errorf("manager configuration not found")
+	}
+
+	if err := manager.AddFromConfig(manager.config); err != nil {
+		manager.logger.Error(err)
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+// configEntry ...
+type configEntry struct {
+	key      string
+	typeName string
+	config   interface{}
+}
+
+// AddFromConfig builds and adds the components declared on the configuration,
+// the components section accepts any registered component type on its type field
+func (manager *Manager) AddFromConfig(config *ManagerConfig) error {
+	entries := make([]*configEntry, 0)
+	entries = appendEntries(entries, FactoryDatabase, config.Dbs)
+	entries = appendEntries(entries, FactoryRedis, config.Redis)
+	entries = appendEntries(entries, FactoryNSQProducer, config.NSQProducers)
+	entries = appendEntries(entries, FactoryNSQConsumer, config.NSQConsumers)
+	entries = appendEntries(entries, FactoryRabbitmqProducer, config.RabbitmqProducers)
+	entries = appendEntries(entries, FactoryRabbitmqConsumer, config.RabbitmqConsumers)
+	entries = appendEntries(entries, FactoryWorkList, config.WorkLists)
+	entries = appendEntries(entries, FactoryBulkWorkList, config.BulkWorkLists)
+	entries = appendEntries(entries, "", config.Webs)
+	entries = appendEntries(entries, "", config.Components)
+
+	for _, entry := range entries {
+		typeName := entry.typeName
+
+		if typeName == "" {
+			var err error
+			switch section := entry.config.(type) {
+			case *ManagerWebConfig:
+				typeName, err = webFactoryType(section.Type)
+			case json.RawMessage:
+				component := &struct {
+					Type string `json:"type"`
+				}{}
+				err = json.Unmarshal(section, component)
+				typeName = component.Type
+			}
+
+			if err != nil {
+				return &ComponentError{Key: entry.key, Kind: "component", Action: "build", Err: err}
+			}
+		}
+
+		if _, err := manager.Build(typeName, entry.key, entry.config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendEntries appends the components of the section, by key order
+func appendEntries[T any](entries []*configEntry, typeName string, section map[string]T) []*configEntry {
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entries = append(entries, &configEntry{key: key, typeName: typeName, config: section[key]})
+	}
+
+	return entries
+}
+
+// addRouteFromConfig adds the route to the web, with the handler and middlewares bound by name
+func (manager *Manager) addRouteFromConfig(w IWeb, route *ManagerWebRouteConfig) error {
+	handler, err := lookupHandler[HandlerFunc](manager.handlers, route.Handler, false)
+	if err != nil {
+		return err
+	}
+
+	middlewares := make([]MiddlewareFunc, 0, len(route.Middlewares))
+	for _, name := range route.Middlewares {
+		middleware, err := lookupHandler[MiddlewareFunc](manager.handlers, name, false)
+		if err != nil {
+			return err
+		}
+		middlewares = append(middlewares, middleware)
+	}
+
+	return w.AddRoute(route.Method, route.Path, handler, middlewares...)
+}
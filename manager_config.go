@@ -1,6 +1,16 @@
 package manager
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
 
 // IConfig ...
 type IConfig interface {
@@ -23,9 +33,169 @@ type IConfig interface {
 	Reload() error
 }
 
+// IConfigSection can be implemented by the configs that can check and decode their sections,
+// the other configs are decoded from the values of Get
+type IConfigSection interface {
+	IsSet(key string) bool
+	UnmarshalKey(key string, obj interface{}) error
+}
+
+// IConfigNotifier can be implemented by the configs that notify their changes
+type IConfigNotifier interface {
+	OnChange(handler func(diff *ConfigDiff))
+}
+
+// ErrConfigKeyNotFound is returned when getting a key that isn't on the config
+var ErrConfigKeyNotFound = errors.New("config key not found")
+
+// ConfigGet gets the value of the key converted to the type, the durations can also be given as text (ex: "5s").
+// an error wrapping ErrConfigKeyNotFound is returned when the key isn't on the config
+func ConfigGet[T any](config IConfig, key string) (T, error) {
+	var value T
+
+	if !configIsSet(config, key) {
+		return value, fmt.Errorf("%w [ key: %s ]", ErrConfigKeyNotFound, key)
+	}
+
+	if duration, ok := any(&value).(*time.Duration); ok {
+		if text, ok := config.Get(key).(string); ok {
+			parsed, err := time.ParseDuration(text)
+			if err != nil {
+				return value, fmt.Errorf("invalid duration [ key: %s ]: %w", key, err)
+			}
+			*duration = parsed
+			return value, nil
+		}
+	}
+
+	if err := configUnmarshalKey(config, key, &value); err != nil {
+		return value, fmt.Errorf("error converting the config value to %T [ key: %s ]: %w", value, key, err)
+	}
+
+	return value, nil
+}
+
+// configIsSet checks if the key is on the config
+func configIsSet(config IConfig, key string) bool {
+	if section, ok := config.(IConfigSection); ok {
+		return section.IsSet(key)
+	}
+
+	return config.Get(key) != nil
+}
+
+// configUnmarshalKey decodes the value of the key into the object
+func configUnmarshalKey(config IConfig, key string, obj interface{}) error {
+	if section, ok := config.(IConfigSection); ok {
+		return section.UnmarshalKey(key, obj)
+	}
+
+	data, err := json.Marshal(config.Get(key))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, obj)
+}
+
+// ConfigChange ...
+type ConfigChange struct {
+	Key string      `json:"key"`
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// ConfigDiff has the keys changed by a reload, with the levels separated by dots
+type ConfigDiff struct {
+	Changes []*ConfigChange `json:"changes"`
+}
+
+// Keys ...
+func (diff *ConfigDiff) Keys() []string {
+	keys := make([]string, 0, len(diff.Changes))
+	for _, change := range diff.Changes {
+		keys = append(keys, change.Key)
+	}
+
+	return keys
+}
+
+// Has checks if the key, or any key below it, was changed
+func (diff *ConfigDiff) Has(key string) bool {
+	key = strings.ToLower(key)
+	for _, change := range diff.Changes {
+		if change.Key == key || strings.HasPrefix(change.Key, key+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redact hides the values of the changed keys with secrets
+func (diff *ConfigDiff) redact(secrets []*configSecret) {
+	keys := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		keys[strings.ToLower(secret.key())] = true
+	}
+
+	for _, change := range diff.Changes {
+		if keys[change.Key] {
+			change.Old = redacted
+			change.New = redacted
+		}
+	}
+}
+
+// newConfigDiff compares two configurations encoded as json
+func newConfigDiff(previous, current []byte) *ConfigDiff {
+	diff := &ConfigDiff{Changes: make([]*ConfigChange, 0)}
+
+	oldValues := flattenConfig(previous)
+	newValues := flattenConfig(current)
+
+	keys := make(map[string]bool)
+	for key := range oldValues {
+		keys[key] = true
+	}
+	for key := range newValues {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		if !reflect.DeepEqual(oldValues[key], newValues[key]) {
+			diff.Changes = append(diff.Changes, &ConfigChange{Key: key, Old: oldValues[key], New: newValues[key]})
+		}
+	}
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Key < diff.Changes[j].Key
+	})
+
+	return diff
+}
+
+// flattenConfig decodes the configuration into its values by lower case key
+func flattenConfig(data []byte) map[string]interface{} {
+	values := make(map[string]interface{})
+	json.Unmarshal(data, &values)
+
+	flat := make(map[string]interface{})
+	flattenConfigValues("", values, flat)
+
+	lower := make(map[string]interface{}, len(flat))
+	for key, value := range flat {
+		lower[strings.ToLower(key)] = value
+	}
+
+	return lower
+}
+
 // AddConfig ...
 func (manager *Manager) AddConfig(key string, config IConfig) error {
+	manager.registryMux.Lock()
 	manager.configs[key] = config
+	manager.registryMux.Unlock()
 	manager.logger.Infof("config %s added", key)
 
 	return nil
@@ -33,9 +203,11 @@ func (manager *Manager) AddConfig(key string, config IConfig) error {
 
 // RemoveConfig ...
 func (manager *Manager) RemoveConfig(key string) (IConfig, error) {
+	manager.registryMux.Lock()
 	config := manager.configs[key]
-
 	delete(manager.configs, key)
+	manager.registryMux.Unlock()
+
 	manager.logger.Infof("config %s removed", key)
 
 	return config, nil
@@ -43,9 +215,89 @@ func (manager *Manager) RemoveConfig(key string) (IConfig, error) {
 
 // GetConfig ...
 func (manager *Manager) GetConfig(key string) IConfig {
-	if config, exists := manager.configs[key]; exists {
+	manager.registryMux.RLock()
+	config, exists := manager.configs[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return config
 	}
 	manager.logger.Infof("config %s doesn't exist", key)
 	return nil
 }
+
+// WatchConfig watches the config for changes, applying the new log level (manager.log.level)
+// and the new number of workers of the work lists (manager.work_lists.<key>.max_workers)
+func (manager *Manager) WatchConfig(config IConfig) error {
+	notifier, ok := config.(IConfigNotifier)
+	if !ok {
+		return fmt.Errorf("the config doesn't notify its changes [ type: %T ]", config)
+	}
+
+	notifier.OnChange(func(diff *ConfigDiff) {
+		manager.applyConfigChanges(config, diff)
+	})
+
+	if watcher, ok := config.(interface{ Watch() error }); ok {
+		return watcher.Watch()
+	}
+
+	return nil
+}
+
+// applyConfigChanges ...
+func (manager *Manager) applyConfigChanges(config IConfig, diff *ConfigDiff) {
+	manager.logger.Infof("config changed [ keys: %s ]", strings.Join(diff.Keys(), ", "))
+
+	if appConfig, ok := config.GetObj().(*AppConfig); ok && appConfig.Manager != nil {
+		manager.registryMux.Lock()
+		manager.config = appConfig.Manager
+		manager.registryMux.Unlock()
+	}
+
+	if diff.Has("manager.log.level") && !manager.isLogExternal {
+		level, err := logger.ParseLevel(config.GetString("manager.log.level"))
+		if err != nil {
+			manager.logger.Errorf("invalid log level [ level: %s ]: %s", config.GetString("manager.log.level"), err)
+		} else {
+			manager.logger.Reconfigure(logger.WithLevel(level))
+		}
+	}
+
+	for _, change := range diff.Changes {
+		for _, section := range []string{"manager.work_lists.", "manager.bulk_work_lists."} {
+			if !strings.HasPrefix(change.Key, section) || !strings.HasSuffix(change.Key, ".max_workers") {
+				continue
+			}
+
+			key := strings.TrimSuffix(strings.TrimPrefix(change.Key, section), ".max_workers")
+			maxWorkers, ok := change.New.(float64)
+			if !ok {
+				continue
+			}
+
+			worklist, ok := manager.findWorkList(key).(IScalableWorkList)
+			if !ok {
+				continue
+			}
+
+			if err := worklist.SetMaxWorkers(int(maxWorkers)); err != nil {
+				manager.logger.Errorf("error changing the number of workers [ work list: %s ]: %s", key, err)
+			}
+		}
+	}
+}
+
+// findWorkList gets the work list by key ignoring the case, as the keys of the config changes are lower case
+func (manager *Manager) findWorkList(key string) IWorkList {
+	manager.registryMux.RLock()
+	defer manager.registryMux.RUnlock()
+
+	for name, worklist := range manager.worklist {
+		if strings.EqualFold(name, key) {
+			return worklist
+		}
+	}
+
+	return nil
+}
@@ -1,21 +1,17 @@
 package manager
 
-import (
-	"database/sql"
-	"sync"
-)
+import "database/sql"
 
+// IDB ...
 type IDB interface {
+	ILifecycle
 	Get() *sql.DB
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
-	Started() bool
 }
 
 // DBConfig ...
 type DBConfig struct {
-	Driver     string `json:"driver"`
-	DataSource string `json:"datasource"`
+	Driver     string `json:"driver" validate:"required"`
+	DataSource string `json:"datasource" validate:"required"`
 }
 
 // NewDBConfig...
@@ -27,18 +23,31 @@ func NewDBConfig(driver, datasource string) *DBConfig {
 }
 
 // AddDB ...
-func (manager *Manager) AddDB(key string, db IDB) error {
+func (manager *Manager) AddDB(key string, db IDB, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
 	manager.dbs[key] = db
+	manager.registryMux.Unlock()
 	manager.logger.Infof("database %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveDB ...
 func (manager *Manager) RemoveDB(key string) (IDB, error) {
-	db := manager.dbs[key]
+	if err := manager.stopRemoved(kindDB, key); err != nil {
+		return nil, err
+	}
 
+	manager.registryMux.Lock()
+	db := manager.dbs[key]
 	delete(manager.dbs, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
+
 	manager.logger.Infof("database %s removed", key)
 
 	return db, nil
@@ -46,7 +55,11 @@ func (manager *Manager) RemoveDB(key string) (IDB, error) {
 
 // GetDB ...
 func (manager *Manager) GetDB(key string) IDB {
-	if db, exists := manager.dbs[key]; exists {
+	manager.registryMux.RLock()
+	db, exists := manager.dbs[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return db
 	}
 	manager.logger.Infof("database %s doesn't exist", key)
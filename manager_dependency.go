@@ -0,0 +1,300 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the kinds of the components
+const (
+	kindFeatureFlags     = "feature flags"
+	kindDB               = "database"
+	kindNSQProducer      = "nsq producer"
+	kindRabbitmqProducer = "rabbitmq producer"
+	kindRedis            = "redis"
+	kindWorkList         = "work list"
+	kindNSQConsumer      = "nsq consumer"
+	kindRabbitmqConsumer = "rabbitmq consumer"
+	kindProcess          = "process"
+	kindWeb              = "web"
+)
+
+// componentConfig ...
+type componentConfig struct {
+	dependsOn       []string
+	stopTimeout     time.Duration
+	readinessChecks []IHealthChecker
+	livenessChecks  []IHealthChecker
+	restart         *RestartConfig
+	state           *StateMachine
+}
+
+// newComponentConfig ...
+func newComponentConfig() *componentConfig {
+	return &componentConfig{
+		state: NewStateMachine(),
+	}
+}
+
+// ComponentOption ...
+type ComponentOption func(config *componentConfig)
+
+// DependsOn declares the components that must be started before this one (and stopped after it)
+func DependsOn(keys ...string) ComponentOption {
+	return func(config *componentConfig) {
+		config.dependsOn = append(config.dependsOn, keys...)
+	}
+}
+
+// node ...
+type node struct {
+	key        string
+	kind       string
+	component  ILifecycle
+	config     *componentConfig
+	explicit   bool
+	depends    []*node
+	dependents []*node
+}
+
+// graph ...
+type graph struct {
+	nodes []*node
+	index map[string]*node
+}
+
+// addComponent must be called holding the registry lock. the keys are unique for all the kinds,
+// so that the dependencies and the states are by key, and the components can't be added while the manager is starting
+func (manager *Manager) addComponent(key string, options ...ComponentOption) error {
+	// the components being started were taken when the manager started, so the component wouldn't be started
+	if manager.state.State() == StateStarting {
+		return fmt.Errorf("%w [ key: %s ]", ErrManagerStarting, key)
+	}
+
+	if _, exists := manager.components[key]; exists {
+		return fmt.Errorf("%w [ key: %s ]", ErrComponentExists, key)
+	}
+
+	config := newComponentConfig()
+	for _, option := range options {
+		option(config)
+	}
+	manager.components[key] = config
+
+	return nil
+}
+
+// removeComponent must be called holding the registry lock
+func (manager *Manager) removeComponent(key string) {
+	delete(manager.components, key)
+}
+
+// kinds returns the registered components grouped by kind, in the order (dbs, producers, redis, work lists,
+// consumers, processes and webs) and sorted by key. the consumers start after the work lists they can feed,
+// and stop before them, so that they don't add works to the work lists already stopped
+func (manager *Manager) kinds() [][]*node {
+	manager.registryMux.RLock()
+	defer manager.registryMux.RUnlock()
+
+	kinds := []struct {
+		kind       string
+		components map[string]ILifecycle
+	}{
+		{kindFeatureFlags, lifecycles(manager.featureFlags)},
+		{kindDB, lifecycles(manager.dbs)},
+		{kindNSQProducer, lifecycles(manager.nsqProducers)},
+		{kindRabbitmqProducer, lifecycles(manager.rabbitmqProducers)},
+		{kindRedis, lifecycles(manager.redis)},
+		{kindWorkList, lifecycles(manager.worklist)},
+		{kindNSQConsumer, lifecycles(manager.nsqConsumers)},
+		{kindRabbitmqConsumer, lifecycles(manager.rabbitmqConsumers)},
+		{kindProcess, lifecycles(manager.processes)},
+		{kindWeb, lifecycles(manager.webs)},
+	}
+
+	result := make([][]*node, 0, len(kinds))
+	for _, kind := range kinds {
+		current := make([]*node, 0, len(kind.components))
+
+		for key, component := range kind.components {
+			config, ok := manager.components[key]
+			if !ok {
+				config = newComponentConfig()
+			}
+
+			current = append(current, &node{
+				key:       key,
+				kind:      kind.kind,
+				component: component,
+				config:    config,
+				explicit:  len(config.dependsOn) > 0,
+			})
+		}
+
+		sort.Slice(current, func(i, j int) bool { return current[i].key < current[j].key })
+		result = append(result, current)
+	}
+
+	return result
+}
+
+// buildGraph creates the dependency graph of the components.
+// the components that don't declare dependencies keep the order by kind
+func (manager *Manager) buildGraph() (*graph, error) {
+	g := &graph{index: make(map[string]*node)}
+
+	var previous []*node
+	for _, current := range manager.kinds() {
+		for _, n := range current {
+			if other, exists := g.index[n.key]; exists {
+				return nil, fmt.Errorf("duplicated component key [ key: %s, kinds: %s, %s ]", n.key, other.kind, n.kind)
+			}
+			g.index[n.key] = n
+
+			if !n.explicit {
+				n.depends = append(n.depends, previous...)
+			}
+		}
+
+		for _, n := range current {
+			if !n.explicit {
+				previous = append(previous, n)
+			}
+		}
+		g.nodes = append(g.nodes, current...)
+	}
+
+	for _, n := range g.nodes {
+		if !n.explicit {
+			continue
+		}
+
+		for _, key := range n.config.dependsOn {
+			dep, exists := g.index[key]
+			if !exists {
+				return nil, fmt.Errorf("unknown dependency [ component: %s, depends on: %s ]", n.key, key)
+			}
+			n.depends = append(n.depends, dep)
+		}
+	}
+
+	for _, n := range g.nodes {
+		for _, dep := range n.depends {
+			dep.dependents = append(dep.dependents, n)
+		}
+	}
+
+	if cycle := g.cycle(); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle detected [ %s ]", strings.Join(cycle, " -> "))
+	}
+
+	return g, nil
+}
+
+// cycle returns the keys of the first dependency cycle found, or nil when there isn't any
+func (g *graph) cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	status := make(map[*node]int)
+	var path []*node
+	var found []string
+
+	var visit func(n *node) bool
+	visit = func(n *node) bool {
+		status[n] = visiting
+		path = append(path, n)
+
+		for _, dep := range n.depends {
+			switch status[dep] {
+			case visiting:
+				for i, p := range path {
+					if p == dep {
+						for _, c := range path[i:] {
+							found = append(found, c.key)
+						}
+						found = append(found, dep.key)
+						return true
+					}
+				}
+			case unvisited:
+				if visit(dep) {
+					return true
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		status[n] = visited
+		return false
+	}
+
+	for _, n := range g.nodes {
+		if status[n] == unvisited && visit(n) {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// run executes the function on every node of the graph, in parallel for independent branches.
+// on start, a node waits for the nodes it depends on and is skipped when any of them failed;
+// on stop (reverse), it waits for its dependents and always runs.
+// it returns the errors of the nodes that failed, in the order they happened
+func (g *graph) run(reverse bool, fn func(n *node) error) []error {
+	done := make(map[*node]chan struct{}, len(g.nodes))
+	for _, n := range g.nodes {
+		done[n] = make(chan struct{})
+	}
+
+	var errs []error
+	failed := make(map[*node]bool)
+	mux := &sync.Mutex{}
+
+	var wg sync.WaitGroup
+	for _, n := range g.nodes {
+		wg.Add(1)
+		go func(n *node) {
+			defer wg.Done()
+			defer close(done[n])
+
+			waitFor := n.depends
+			if reverse {
+				waitFor = n.dependents
+			}
+
+			skip := false
+			for _, other := range waitFor {
+				<-done[other]
+
+				mux.Lock()
+				skip = skip || (!reverse && failed[other])
+				mux.Unlock()
+			}
+
+			if skip {
+				mux.Lock()
+				failed[n] = true
+				mux.Unlock()
+				return
+			}
+
+			if err := fn(n); err != nil {
+				mux.Lock()
+				failed[n] = true
+				errs = append(errs, err)
+				mux.Unlock()
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	return errs
+}
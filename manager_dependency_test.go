@@ -0,0 +1,140 @@
+package manager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDependencyOrder(t *testing.T) {
+	events := &fakeEvents{}
+	m := NewManager(WithRunInBackground(true))
+
+	m.AddWorkList("works", &fakeWorkList{newFakeComponent("works", events)})
+	m.AddProcess("process", newFakeComponent("process", events))
+	m.AddProcess("api", newFakeComponent("api", events), DependsOn("cache"))
+	m.AddProcess("cache", newFakeComponent("cache", events))
+
+	if err := m.Start(); err != nil {
+		t.Fatalf("error starting the manager: %s", err)
+	}
+
+	if err := m.Stop(); err != nil {
+		t.Fatalf("error stopping the manager: %s", err)
+	}
+
+	tests := []struct {
+		before string
+		after  string
+	}{
+		{before: "start works", after: "start process"},
+		{before: "start works", after: "start cache"},
+		{before: "start cache", after: "start api"},
+		{before: "stop api", after: "stop cache"},
+		{before: "stop process", after: "stop works"},
+		{before: "stop cache", after: "stop works"},
+	}
+
+	for _, test := range tests {
+		before, after := events.index(test.before), events.index(test.after)
+		if before < 0 || after < 0 || before > after {
+			t.Errorf("%q happened at %d and %q at %d, expected %q first", test.before, before, test.after, after, test.before)
+		}
+	}
+}
+
+func TestDependencyGraphErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		add      func(m *Manager)
+		expected string
+	}{
+		{
+			name: "cycle",
+			add: func(m *Manager) {
+				m.AddProcess("a", newFakeComponent("a", nil), DependsOn("b"))
+				m.AddProcess("b", newFakeComponent("b", nil), DependsOn("c"))
+				m.AddProcess("c", newFakeComponent("c", nil), DependsOn("a"))
+			},
+			expected: "dependency cycle detected [ a -> b -> c -> a ]",
+		},
+		{
+			name: "self dependency",
+			add: func(m *Manager) {
+				m.AddProcess("a", newFakeComponent("a", nil), DependsOn("a"))
+			},
+			expected: "dependency cycle detected [ a -> a ]",
+		},
+		{
+			name: "unknown dependency",
+			add: func(m *Manager) {
+				m.AddProcess("a", newFakeComponent("a", nil), DependsOn("missing"))
+			},
+			expected: "unknown dependency [ component: a, depends on: missing ]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager(WithRunInBackground(true))
+			test.add(m)
+
+			err := m.Start()
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("starting got the error %v, expected %s", err, test.expected)
+			}
+
+			if state := m.state.State(); state != StateFailed {
+				t.Errorf("the manager is %s, expected %s", state, StateFailed)
+			}
+		})
+	}
+}
+
+func TestStartRollback(t *testing.T) {
+	events := &fakeEvents{}
+	m := NewManager(WithRunInBackground(true))
+
+	db := newFakeComponent("db", events)
+	api := newFakeComponent("api", events)
+	api.startErr = errors.New("listen failed")
+	worker := newFakeComponent("worker", events)
+
+	m.AddProcess("db", db)
+	m.AddProcess("api", api, DependsOn("db"))
+	m.AddProcess("worker", worker, DependsOn("api"))
+
+	err := m.Start()
+
+	var managerErr *ManagerError
+	if !errors.As(err, &managerErr) || !errors.Is(err, api.startErr) {
+		t.Fatalf("starting got the error %v, expected a *ManagerError with the error of the api", err)
+	}
+
+	if starts, stops := db.counts(); starts != 1 || stops != 1 {
+		t.Errorf("the db was started %d and stopped %d times, expected to be rolled back once", starts, stops)
+	}
+
+	if starts, _ := worker.counts(); starts != 0 {
+		t.Errorf("the worker was started %d times, its dependency failed", starts)
+	}
+
+	tests := []struct {
+		key      string
+		expected State
+	}{
+		{key: "db", expected: StateStopped},
+		{key: "api", expected: StateFailed},
+		{key: "worker", expected: StateCreated},
+	}
+
+	for _, test := range tests {
+		if state := m.State(test.key).State; state != test.expected {
+			t.Errorf("the %s is %s, expected %s", test.key, state, test.expected)
+		}
+	}
+
+	if state := m.state.State(); state != StateFailed {
+		t.Errorf("the manager is %s, expected %s", state, StateFailed)
+	}
+}
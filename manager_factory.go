@@ -0,0 +1,132 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ComponentFactory creates a component from its raw configuration section
+type ComponentFactory func(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error)
+
+var (
+	factories    = make(map[string]ComponentFactory)
+	factoriesMux sync.RWMutex
+)
+
+// RegisterFactory registers the factory of a component type, replacing the one with the same type
+func RegisterFactory(typeName string, factory ComponentFactory) {
+	factoriesMux.Lock()
+	defer factoriesMux.Unlock()
+
+	factories[typeName] = factory
+}
+
+// Factories returns the registered component types
+func Factories() []string {
+	factoriesMux.RLock()
+	defer factoriesMux.RUnlock()
+
+	types := make([]string, 0, len(factories))
+	for typeName := range factories {
+		types = append(types, typeName)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// Build creates a component of the type with the factory registered for it and adds it to the manager with the name.
+// the raw configuration can be json bytes or any value that can be encoded as json,
+// and its depends_on field declares the dependencies of the component
+func (manager *Manager) Build(typeName, name string, rawConfig interface{}, options ...ComponentOption) (ILifecycle, error) {
+	factoriesMux.RLock()
+	factory, exists := factories[typeName]
+	factoriesMux.RUnlock()
+
+	if !exists {
+		err := fmt.Errorf("unknown component type [ type: %s, name: %s, available types: %s ]", typeName, name, strings.Join(Factories(), ", "))
+		manager.logger.Error(err)
+		return nil, err
+	}
+
+	raw, err := rawJSON(rawConfig)
+	if err != nil {
+		return nil, &ComponentError{Key: name, Kind: typeName, Action: "build", Err: err}
+	}
+
+	component, err := factory(manager, name, raw)
+	if err != nil {
+		return nil, &ComponentError{Key: name, Kind: typeName, Action: "build", Err: err}
+	}
+
+	componentConfig := &ManagerComponentConfig{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, componentConfig); err != nil {
+			return nil, &ComponentError{Key: name, Kind: typeName, Action: "build", Err: err}
+		}
+	}
+	options = append(componentConfig.options(), options...)
+
+	if err := manager.add(name, component, options...); err != nil {
+		return nil, err
+	}
+
+	return component, nil
+}
+
+// add adds the component to the registry of its kind, the ones without a specific kind are added as processes
+func (manager *Manager) add(name string, component ILifecycle, options ...ComponentOption) error {
+	switch value := component.(type) {
+	case IDB:
+		return manager.AddDB(name, value, options...)
+	case IRedis:
+		return manager.AddRedis(name, value, options...)
+	case INSQConsumer:
+		return manager.AddNSQConsumer(name, value, options...)
+	case INSQProducer:
+		return manager.AddNSQProducer(name, value, options...)
+	case *SimpleRabbitmqConsumer:
+		return manager.AddRabbitmqConsumer(name, value, options...)
+	case IRabbitmqProducer:
+		return manager.AddRabbitmqProducer(name, value, options...)
+	case IWorkList:
+		return manager.AddWorkList(name, value, options...)
+	case IWeb:
+		return manager.AddWeb(name, value, options...)
+	case IFeatureFlags:
+		return manager.AddFeatureFlags(name, value, options...)
+	default:
+		return manager.AddProcess(name, value, options...)
+	}
+}
+
+// rawJSON ...
+func rawJSON(rawConfig interface{}) (json.RawMessage, error) {
+	switch value := rawConfig.(type) {
+	case nil:
+		return nil, nil
+	case json.RawMessage:
+		return value, nil
+	case []byte:
+		return value, nil
+	case string:
+		return json.RawMessage(value), nil
+	default:
+		return json.Marshal(normalize(value))
+	}
+}
+
+// decodeRawConfig decodes the raw configuration of a factory, setting the empty fields with their default tag
+// and validating the fields with their validate tag, as the configurations loaded from a file
+func decodeRawConfig(rawConfig json.RawMessage, obj interface{}) error {
+	if len(rawConfig) > 0 {
+		if err := json.Unmarshal(rawConfig, obj); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+
+	return validateConfig("", obj)
+}
@@ -0,0 +1,239 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// built-in component types
+const (
+	FactoryDatabase         = "database"
+	FactoryPostgres         = "postgres"
+	FactoryMysql            = "mysql"
+	FactoryRedis            = "redis"
+	FactoryNSQProducer      = "nsq_producer"
+	FactoryNSQConsumer      = "nsq_consumer"
+	FactoryRabbitmqProducer = "rabbitmq_producer"
+	FactoryRabbitmqConsumer = "rabbitmq_consumer"
+	FactoryWorkList         = "work_list"
+	FactoryBulkWorkList     = "bulk_work_list"
+	FactoryWebHttp          = "web_http"
+	FactoryWebEcho          = "web_echo"
+	FactoryWebServer        = "web_server"
+	FactoryFeatureFlags     = "feature_flags"
+)
+
+func init() {
+	RegisterFactory(FactoryDatabase, dbFactory(""))
+	RegisterFactory(FactoryPostgres, dbFactory("postgres"))
+	RegisterFactory(FactoryMysql, dbFactory("mysql"))
+	RegisterFactory(FactoryRedis, redisFactory)
+	RegisterFactory(FactoryNSQProducer, nsqProducerFactory)
+	RegisterFactory(FactoryNSQConsumer, nsqConsumerFactory)
+	RegisterFactory(FactoryRabbitmqProducer, rabbitmqProducerFactory)
+	RegisterFactory(FactoryRabbitmqConsumer, rabbitmqConsumerFactory)
+	RegisterFactory(FactoryWorkList, workListFactory)
+	RegisterFactory(FactoryBulkWorkList, bulkWorkListFactory)
+	RegisterFactory(FactoryWebHttp, webFactory(func(manager *Manager, host string) IWeb { return manager.NewSimpleWebHttp(host) }))
+	RegisterFactory(FactoryWebEcho, webFactory(func(manager *Manager, host string) IWeb { return manager.NewSimpleWebEcho(host) }))
+	RegisterFactory(FactoryFeatureFlags, featureFlagsFactory)
+	RegisterFactory(FactoryWebServer, webFactory(func(manager *Manager, host string) IWeb { return manager.NewSimpleWebServer(host) }))
+}
+
+// dbFactory creates databases, with the driver of the configuration when it isn't given
+func dbFactory(driver string) ComponentFactory {
+	return func(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+		// the driver is set before decoding, so that the required driver is validated with the driver of the factory
+		config := &ManagerDBConfig{DBConfig: DBConfig{Driver: driver}}
+		if err := decodeRawConfig(rawConfig, config); err != nil {
+			return nil, err
+		}
+
+		if driver != "" {
+			config.Driver = driver
+		}
+
+		return manager.NewSimpleDB(&config.DBConfig), nil
+	}
+}
+
+func redisFactory(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+	config := &ManagerRedisConfig{}
+	if err := decodeRawConfig(rawConfig, config); err != nil {
+		return nil, err
+	}
+
+	return manager.NewSimpleRedis(&config.RedisConfig), nil
+}
+
+func nsqProducerFactory(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+	config := &ManagerNSQProducerConfig{}
+	if err := decodeRawConfig(rawConfig, config); err != nil {
+		return nil, err
+	}
+
+	return manager.NewSimpleNSQProducer(&config.NSQConfig)
+}
+
+func nsqConsumerFactory(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+	config := &ManagerNSQConsumerConfig{}
+	if err := decodeRawConfig(rawConfig, config); err != nil {
+		return nil, err
+	}
+
+	handler, err := lookupHandler[INSQHandler](manager.handlers, config.Handler, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.NewSimpleNSQConsumer(&config.NSQConfig, handler)
+}
+
+func rabbitmqProducerFactory(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+	config := &ManagerRabbitmqProducerConfig{}
+	if err := decodeRawConfig(rawConfig, config); err != nil {
+		return nil, err
+	}
+
+	return manager.NewSimpleRabbitmqProducer(&config.RabbitmqConfig)
+}
+
+func rabbitmqConsumerFactory(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+	config := &ManagerRabbitmqConsumerConfig{}
+	if err := decodeRawConfig(rawConfig, config); err != nil {
+		return nil, err
+	}
+
+	handler, err := lookupHandler[RabbitmqHandler](manager.handlers, config.Handler, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.NewSimpleRabbitmqConsumer(&config.RabbitmqConfig, config.Queue, config.BindingKey, config.Tag, handler)
+}
+
+func workListFactory(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+	config := &ManagerWorkListConfig{}
+	if err := decodeRawConfig(rawConfig, config); err != nil {
+		return nil, err
+	}
+
+	if config.Name == "" {
+		config.Name = name
+	}
+
+	handler, err := lookupHandler[WorkHandler](manager.handlers, config.Handler, false)
+	if err != nil {
+		return nil, err
+	}
+
+	recoverHandler, err := lookupHandler[WorkRecoverHandler](manager.handlers, config.RecoverHandler, true)
+	if err != nil {
+		return nil, err
+	}
+
+	recoverWastedRetriesHandler, err := lookupHandler[WorkRecoverWastedRetriesHandler](manager.handlers, config.RecoverWastedRetriesHandler, true)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := manager.newWorkListStorage(config.Name, config.Mode, config.MaxRetries, &config.WorkListStorageConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.newSimpleWorkList(&config.WorkListConfig, list, handler, recoverHandler, recoverWastedRetriesHandler), nil
+}
+
+func bulkWorkListFactory(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+	config := &ManagerBulkWorkListConfig{}
+	if err := decodeRawConfig(rawConfig, config); err != nil {
+		return nil, err
+	}
+
+	if config.Name == "" {
+		config.Name = name
+	}
+
+	handler, err := lookupHandler[BulkWorkHandler](manager.handlers, config.Handler, false)
+	if err != nil {
+		return nil, err
+	}
+
+	recoverHandler, err := lookupHandler[BulkWorkRecoverHandler](manager.handlers, config.RecoverHandler, true)
+	if err != nil {
+		return nil, err
+	}
+
+	recoverWastedRetriesHandler, err := lookupHandler[BulkWorkRecoverWastedRetriesHandler](manager.handlers, config.RecoverWastedRetriesHandler, true)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := manager.newWorkListStorage(config.Name, config.Mode, config.MaxRetries, &config.WorkListStorageConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.newSimpleBulkWorkList(&config.BulkWorkListConfig, list, handler, recoverHandler, recoverWastedRetriesHandler), nil
+}
+
+// webFactory creates web servers with the routes of the configuration
+func webFactory(newWeb func(manager *Manager, host string) IWeb) ComponentFactory {
+	return func(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+		config := &ManagerWebConfig{}
+		if err := decodeRawConfig(rawConfig, config); err != nil {
+			return nil, err
+		}
+
+		w := newWeb(manager, config.Host)
+		for _, route := range config.Routes {
+			if err := manager.addRouteFromConfig(w, route); err != nil {
+				return nil, err
+			}
+		}
+
+		return w, nil
+	}
+}
+
+// webFactoryType returns the factory type of the web type of the configuration
+func webFactoryType(webType string) (string, error) {
+	switch webType {
+	case "", "http":
+		return FactoryWebHttp, nil
+	case "echo":
+		return FactoryWebEcho, nil
+	case "web":
+		return FactoryWebServer, nil
+	default:
+		return "", fmt.Errorf("unknown web type [ type: %s ]", webType)
+	}
+}
+
+// featureFlagsFactory creates the feature flags of a config added to the manager, or of the application config
+func featureFlagsFactory(manager *Manager, name string, rawConfig json.RawMessage) (ILifecycle, error) {
+	config := &struct {
+		Config  string `json:"config"`
+		Section string `json:"section"`
+	}{}
+	if err := decodeRawConfig(rawConfig, config); err != nil {
+		return nil, err
+	}
+
+	source := manager.appConfig
+	if config.Config != "" {
+		source = manager.GetConfig(config.Config)
+	}
+
+	if source == nil {
+		return nil, fmt.Errorf("feature flags config not found [ config: %s ]", config.Config)
+	}
+
+	options := make([]FeatureFlagsOption, 0)
+	if config.Section != "" {
+		options = append(options, WithFlagsSection(config.Section))
+	}
+
+	return manager.NewFeatureFlags(source, options...)
+}
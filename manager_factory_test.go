@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBuildAppliesTheConfigTags(t *testing.T) {
+	tests := []struct {
+		name               string
+		rawConfig          string
+		expectedMaxWorkers int
+		expectedSleepTime  time.Duration
+		expectedViolation  string
+	}{
+		{name: "defaults", rawConfig: `{"handler": "work"}`, expectedMaxWorkers: 1, expectedSleepTime: time.Second},
+		{name: "values", rawConfig: `{"handler": "work", "max_workers": 3, "sleep_time": 5000000}`, expectedMaxWorkers: 3, expectedSleepTime: 5 * time.Millisecond},
+		{name: "invalid values", rawConfig: `{"handler": "work", "max_workers": -1}`, expectedViolation: "max_workers"},
+	}
+
+	handlers := NewHandlerRegistry().Register("work", func(id string, data interface{}) error { return nil })
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager(WithHandlers(handlers))
+
+			component, err := m.Build(FactoryWorkList, "works", test.rawConfig)
+			if test.expectedViolation != "" {
+				var validationErr *ConfigValidationError
+				if !errors.As(err, &validationErr) || validationErr.Violations[0].Key != test.expectedViolation {
+					t.Fatalf("building got the error %v, expected a violation of %s", err, test.expectedViolation)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error building the work list: %s", err)
+			}
+
+			config := component.(*SimpleWorkList).config
+			if config.MaxWorkers != test.expectedMaxWorkers || config.SleepTime != test.expectedSleepTime {
+				t.Errorf("the work list has %d workers and sleeps %s, expected %d workers and %s",
+					config.MaxWorkers, config.SleepTime, test.expectedMaxWorkers, test.expectedSleepTime)
+			}
+		})
+	}
+}
+
+func TestBuildDatabaseWithTheDriverOfTheFactory(t *testing.T) {
+	m := NewManager()
+
+	if _, err := m.Build(FactoryPostgres, "db", `{"datasource": "postgres://localhost/db"}`); err != nil {
+		t.Errorf("error building the database with the driver of the factory: %s", err)
+	}
+
+	var validationErr *ConfigValidationError
+	if _, err := m.Build(FactoryDatabase, "other", `{"datasource": "postgres://localhost/db"}`); !errors.As(err, &validationErr) {
+		t.Errorf("building the database without a driver got the error %v, expected a violation of the driver", err)
+	}
+}
@@ -0,0 +1,54 @@
+package manager
+
+// IFeatureFlags ...
+type IFeatureFlags interface {
+	ILifecycle
+	IsEnabled(key string, flagContext FlagContext) bool
+	Variant(key string, flagContext FlagContext) string
+	Evaluate(key string, flagContext FlagContext) *FlagEvaluation
+	AuditLog() []*FlagChange
+}
+
+// AddFeatureFlags ...
+func (manager *Manager) AddFeatureFlags(key string, flags IFeatureFlags, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
+	manager.featureFlags[key] = flags
+	manager.registryMux.Unlock()
+	manager.logger.Infof("feature flags %s added", key)
+
+	return manager.startAdded(key)
+}
+
+// RemoveFeatureFlags ...
+func (manager *Manager) RemoveFeatureFlags(key string) (IFeatureFlags, error) {
+	if err := manager.stopRemoved(kindFeatureFlags, key); err != nil {
+		return nil, err
+	}
+
+	manager.registryMux.Lock()
+	flags := manager.featureFlags[key]
+	delete(manager.featureFlags, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
+
+	manager.logger.Infof("feature flags %s removed", key)
+
+	return flags, nil
+}
+
+// GetFeatureFlags ...
+func (manager *Manager) GetFeatureFlags(key string) IFeatureFlags {
+	manager.registryMux.RLock()
+	flags, exists := manager.featureFlags[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
+		return flags
+	}
+	manager.logger.Infof("feature flags %s doesn't exist", key)
+	return nil
+}
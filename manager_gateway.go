@@ -7,7 +7,9 @@ type IGateway interface {
 
 // AddGateway ...
 func (manager *Manager) AddGateway(key string, gateway IGateway) error {
+	manager.registryMux.Lock()
 	manager.gateways[key] = gateway
+	manager.registryMux.Unlock()
 	manager.logger.Infof("gateway %s added", key)
 
 	return nil
@@ -15,9 +17,11 @@ func (manager *Manager) AddGateway(key string, gateway IGateway) error {
 
 // RemoveGateway ...
 func (manager *Manager) RemoveGateway(key string) (IGateway, error) {
+	manager.registryMux.Lock()
 	gateway := manager.gateways[key]
+	delete(manager.gateways, key)
+	manager.registryMux.Unlock()
 
-	delete(manager.configs, key)
 	manager.logger.Infof("gateway %s removed", key)
 
 	return gateway, nil
@@ -25,7 +29,11 @@ func (manager *Manager) RemoveGateway(key string) (IGateway, error) {
 
 // GetGateway ...
 func (manager *Manager) GetGateway(key string) IGateway {
-	if gateway, exists := manager.gateways[key]; exists {
+	manager.registryMux.RLock()
+	gateway, exists := manager.gateways[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return gateway
 	}
 	manager.logger.Infof("gateway %s doesn't exist", key)
@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// HandlerRegistry keeps the handlers by name, so that the components declared on the configuration can use them
+type HandlerRegistry struct {
+	handlers map[string]interface{}
+	mux      sync.RWMutex
+}
+
+// NewHandlerRegistry ...
+func NewHandlerRegistry() *HandlerRegistry {
+	return &HandlerRegistry{
+		handlers: make(map[string]interface{}),
+	}
+}
+
+// Register adds the handler with the name, it can be any of the handlers used by the components
+// (INSQHandler, RabbitmqHandler, WorkHandler, BulkWorkHandler, the web handlers and middlewares, ...)
+func (registry *HandlerRegistry) Register(name string, handler interface{}) *HandlerRegistry {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+
+	registry.handlers[name] = handler
+
+	return registry
+}
+
+// Get ...
+func (registry *HandlerRegistry) Get(name string) (interface{}, bool) {
+	if registry == nil {
+		return nil, false
+	}
+
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+
+	handler, exists := registry.handlers[name]
+	return handler, exists
+}
+
+// lookupHandler returns the handler with the name converted to the type T,
+// an empty name is allowed for the optional handlers and returns the zero value
+func lookupHandler[T any](registry *HandlerRegistry, name string, optional bool) (T, error) {
+	var zero T
+
+	if name == "" {
+		if optional {
+			return zero, nil
+		}
+		return zero, fmt.Errorf("handler name is missing")
+	}
+
+	handler, exists := registry.Get(name)
+	if !exists {
+		return zero, fmt.Errorf("handler isn't registered [ name: %s ]", name)
+	}
+
+	if typed, ok := handler.(T); ok {
+		return typed, nil
+	}
+
+	// allows the plain functions to be used as the named function types
+	target := reflect.TypeOf(&zero).Elem()
+	if value := reflect.ValueOf(handler); value.IsValid() && value.Type().ConvertibleTo(target) {
+		return value.Convert(target).Interface().(T), nil
+	}
+
+	return zero, fmt.Errorf("handler has an invalid type [ name: %s, type: %T, expected: %s ]", name, handler, target)
+}
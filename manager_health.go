@@ -0,0 +1,197 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultHealthCheckTimeout = 5 * time.Second
+
+// IHealthChecker can be implemented by the components that are able to check their own health
+type IHealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthCheckFunc ...
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthCheck ...
+func (f HealthCheckFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthStatus ...
+type HealthStatus string
+
+const (
+	// HealthStatusUp when the component is live and ready
+	HealthStatusUp HealthStatus = "up"
+	// HealthStatusDegraded when the component is live but not ready
+	HealthStatusDegraded HealthStatus = "degraded"
+	// HealthStatusDown when the component is not live
+	HealthStatusDown HealthStatus = "down"
+)
+
+// ComponentHealth ...
+type ComponentHealth struct {
+	Key      string        `json:"key"`
+	Kind     string        `json:"kind"`
+	Status   HealthStatus  `json:"status"`
+	Live     bool          `json:"live"`
+	Ready    bool          `json:"ready"`
+	Errors   []string      `json:"errors,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// HealthReport ...
+type HealthReport struct {
+	Status     HealthStatus                `json:"status"`
+	Live       bool                        `json:"live"`
+	Ready      bool                        `json:"ready"`
+	CheckedAt  time.Time                   `json:"checked_at"`
+	Components map[string]*ComponentHealth `json:"components"`
+}
+
+// WithHealthCheck adds readiness checks to the component, when they fail the component isn't ready
+func WithHealthCheck(checkers ...IHealthChecker) ComponentOption {
+	return func(config *componentConfig) {
+		config.readinessChecks = append(config.readinessChecks, checkers...)
+	}
+}
+
+// WithLivenessCheck adds liveness checks to the component, when they fail the component isn't live nor ready
+func WithLivenessCheck(checkers ...IHealthChecker) ComponentOption {
+	return func(config *componentConfig) {
+		config.livenessChecks = append(config.livenessChecks, checkers...)
+	}
+}
+
+// Health runs concurrently the health checks of all the components.
+// a component is ready when it is started and all its checks pass (including its own, when it implements IHealthChecker),
+// and it is live while its liveness checks pass. the manager is ready when it is started and all the components are ready
+func (manager *Manager) Health(ctx context.Context) *HealthReport {
+	report := &HealthReport{
+		Live:       true,
+		Ready:      manager.Started(),
+		CheckedAt:  time.Now(),
+		Components: make(map[string]*ComponentHealth),
+	}
+
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+
+	for _, kind := range manager.kinds() {
+		for _, n := range kind {
+			wg.Add(1)
+			go func(n *node) {
+				defer wg.Done()
+
+				health := manager.componentHealth(ctx, n)
+
+				mux.Lock()
+				defer mux.Unlock()
+
+				report.Components[n.key] = health
+				report.Live = report.Live && health.Live
+				report.Ready = report.Ready && health.Ready
+			}(n)
+		}
+	}
+	wg.Wait()
+
+	report.Status = healthStatus(report.Live, report.Ready)
+
+	return report
+}
+
+func (manager *Manager) componentHealth(ctx context.Context, n *node) *ComponentHealth {
+	start := time.Now()
+	health := &ComponentHealth{
+		Key:   n.key,
+		Kind:  n.kind,
+		Live:  true,
+		Ready: n.component.Started(),
+	}
+
+	if !health.Ready {
+		health.Errors = append(health.Errors, "not started")
+	}
+
+	readiness := n.config.readinessChecks
+	if checker, ok := n.component.(IHealthChecker); ok && n.component.Started() {
+		readiness = append([]IHealthChecker{checker}, readiness...)
+	}
+
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+
+	check := func(checker IHealthChecker, liveness bool) {
+		defer wg.Done()
+
+		if err := manager.runHealthCheck(ctx, checker); err != nil {
+			mux.Lock()
+			defer mux.Unlock()
+
+			health.Errors = append(health.Errors, err.Error())
+			health.Ready = false
+			if liveness {
+				health.Live = false
+			}
+		}
+	}
+
+	for _, checker := range readiness {
+		wg.Add(1)
+		go check(checker, false)
+	}
+
+	for _, checker := range n.config.livenessChecks {
+		wg.Add(1)
+		go check(checker, true)
+	}
+	wg.Wait()
+
+	health.Status = healthStatus(health.Live, health.Ready)
+	health.Duration = time.Since(start)
+
+	return health
+}
+
+// checkTimeout returns the health check timeout, or the default one when it isn't set
+func (manager *Manager) checkTimeout() time.Duration {
+	if manager.healthCheckTimeout <= 0 {
+		return defaultHealthCheckTimeout
+	}
+
+	return manager.healthCheckTimeout
+}
+
+// runHealthCheck runs the check without waiting beyond the health check timeout
+func (manager *Manager) runHealthCheck(ctx context.Context, checker IHealthChecker) error {
+	ctx, cancel := context.WithTimeout(ctx, manager.checkTimeout())
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- checker.HealthCheck(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func healthStatus(live, ready bool) HealthStatus {
+	switch {
+	case !live:
+		return HealthStatusDown
+	case !ready:
+		return HealthStatusDegraded
+	default:
+		return HealthStatusUp
+	}
+}
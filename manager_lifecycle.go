@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"context"
+	"sync"
+)
+
+// ILifecycle is the lifecycle shared by all the components managed by the manager.
+// Start and Stop must honour the deadline and the cancellation of the context
+type ILifecycle interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Started() bool
+}
+
+// ILegacyLifecycle is the lifecycle of the components written with the wait group pattern
+type ILegacyLifecycle interface {
+	Start(waitGroup ...*sync.WaitGroup) error
+	Stop(waitGroup ...*sync.WaitGroup) error
+	Started() bool
+}
+
+// LegacyLifecycle adapts an ILegacyLifecycle component to ILifecycle
+type LegacyLifecycle struct {
+	component ILegacyLifecycle
+}
+
+// NewLegacyLifecycle ...
+func NewLegacyLifecycle(component ILegacyLifecycle) *LegacyLifecycle {
+	return &LegacyLifecycle{
+		component: component,
+	}
+}
+
+// Start ...
+func (legacy *LegacyLifecycle) Start(ctx context.Context) error {
+	return legacy.execute(ctx, legacy.component.Start)
+}
+
+// Stop ...
+func (legacy *LegacyLifecycle) Stop(ctx context.Context) error {
+	return legacy.execute(ctx, legacy.component.Stop)
+}
+
+// Started ...
+func (legacy *LegacyLifecycle) Started() bool {
+	return legacy.component.Started()
+}
+
+// Component returns the adapted component
+func (legacy *LegacyLifecycle) Component() ILegacyLifecycle {
+	return legacy.component
+}
+
+func (legacy *LegacyLifecycle) execute(ctx context.Context, action func(waitGroup ...*sync.WaitGroup) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		result <- action(wg)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// lifecycles ...
+func lifecycles[T ILifecycle](components map[string]T) map[string]ILifecycle {
+	result := make(map[string]ILifecycle, len(components))
+	for key, component := range components {
+		result[key] = component
+	}
+
+	return result
+}
@@ -1,9 +1,6 @@
 package manager
 
-import (
-	"github.com/nsqio/go-nsq"
-	"sync"
-)
+import "github.com/nsqio/go-nsq"
 
 type INSQHandler interface {
 	HandleMessage(message *nsq.Message) error
@@ -11,25 +8,36 @@ type INSQHandler interface {
 
 // INSQConsumer ...
 type INSQConsumer interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	ILifecycle
 	HandleMessage(message *nsq.Message) error
-	Started() bool
 }
 
 // AddNSQConsumer ...
-func (manager *Manager) AddNSQConsumer(key string, nsqConsumer INSQConsumer) error {
+func (manager *Manager) AddNSQConsumer(key string, nsqConsumer INSQConsumer, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
 	manager.nsqConsumers[key] = nsqConsumer
+	manager.registryMux.Unlock()
 	manager.logger.Infof("consumer %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveNSQConsumer ...
 func (manager *Manager) RemoveNSQConsumer(key string) (INSQConsumer, error) {
+	if err := manager.stopRemoved(kindNSQConsumer, key); err != nil {
+		return nil, err
+	}
+
+	manager.registryMux.Lock()
 	nsqConsumer := manager.nsqConsumers[key]
+	delete(manager.nsqConsumers, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
 
-	delete(manager.processes, key)
 	manager.logger.Infof("consumer %s removed", key)
 
 	return nsqConsumer, nil
@@ -37,7 +45,11 @@ func (manager *Manager) RemoveNSQConsumer(key string) (INSQConsumer, error) {
 
 // GetNSQConsumer ...
 func (manager *Manager) GetNSQConsumer(key string) INSQConsumer {
-	if nsqConsumer, exists := manager.nsqConsumers[key]; exists {
+	manager.registryMux.RLock()
+	nsqConsumer, exists := manager.nsqConsumers[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return nsqConsumer
 	}
 	manager.logger.Infof("consumer %s doesn't exist", key)
@@ -1,29 +1,38 @@
 package manager
 
-import "sync"
-
 // INSQProducer ...
 type INSQProducer interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	ILifecycle
 	Publish(topic string, body []byte, maxRetries int) error
 	Ping() error
-	Started() bool
 }
 
 // AddNSQProducer ...
-func (manager *Manager) AddNSQProducer(key string, nsqProducer INSQProducer) error {
+func (manager *Manager) AddNSQProducer(key string, nsqProducer INSQProducer, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
 	manager.nsqProducers[key] = nsqProducer
+	manager.registryMux.Unlock()
 	manager.logger.Infof("nsq producer %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveNSQProducer ...
 func (manager *Manager) RemoveNSQProducer(key string) (INSQProducer, error) {
+	if err := manager.stopRemoved(kindNSQProducer, key); err != nil {
+		return nil, err
+	}
+
+	manager.registryMux.Lock()
 	process := manager.nsqProducers[key]
+	delete(manager.nsqProducers, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
 
-	delete(manager.processes, key)
 	manager.logger.Infof("nsq producer %s removed", key)
 
 	return process, nil
@@ -31,7 +40,11 @@ func (manager *Manager) RemoveNSQProducer(key string) (INSQProducer, error) {
 
 // GetNSQProducer ...
 func (manager *Manager) GetNSQProducer(key string) INSQProducer {
-	if process, exists := manager.nsqProducers[key]; exists {
+	manager.registryMux.RLock()
+	process, exists := manager.nsqProducers[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return process
 	}
 	manager.logger.Infof("nsq producer %s doesn't exist", key)
@@ -1,27 +1,36 @@
 package manager
 
-import "sync"
-
 // IProcess ...
 type IProcess interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
-	Started() bool
+	ILifecycle
 }
 
 // AddProcess ...
-func (manager *Manager) AddProcess(key string, process IProcess) error {
+func (manager *Manager) AddProcess(key string, process IProcess, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
 	manager.processes[key] = process
+	manager.registryMux.Unlock()
 	manager.logger.Infof("process %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveProcess ...
 func (manager *Manager) RemoveProcess(key string) (IProcess, error) {
-	process := manager.processes[key]
+	if err := manager.stopRemoved(kindProcess, key); err != nil {
+		return nil, err
+	}
 
+	manager.registryMux.Lock()
+	process := manager.processes[key]
 	delete(manager.processes, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
+
 	manager.logger.Infof("process %s removed", key)
 
 	return process, nil
@@ -29,7 +38,11 @@ func (manager *Manager) RemoveProcess(key string) (IProcess, error) {
 
 // GetProcess ...
 func (manager *Manager) GetProcess(key string) IProcess {
-	if process, exists := manager.processes[key]; exists {
+	manager.registryMux.RLock()
+	process, exists := manager.processes[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return process
 	}
 	manager.logger.Infof("process %s doesn't exist", key)
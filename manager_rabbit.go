@@ -2,7 +2,7 @@ package manager
 
 // RabbitmqConfig ...
 type RabbitmqConfig struct {
-	Uri          string `json:"uri"`
+	Uri          string `json:"uri" validate:"required"`
 	Exchange     string `json:"exchange"`
 	ExchangeType string `json:"exchange_type"`
 }
@@ -1,32 +1,40 @@
 package manager
 
-import (
-	"github.com/streadway/amqp"
-	"sync"
-)
+import "github.com/streadway/amqp"
 
 type RabbitmqHandler func(message amqp.Delivery) error
 
 // IRabbitmqConsumer ...
 type IRabbitmqConsumer interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
-	Started() bool
+	ILifecycle
 }
 
 // AddRabbitmqConsumer ...
-func (manager *Manager) AddRabbitmqConsumer(key string, rabbitmqConsumer IRabbitmqConsumer) error {
+func (manager *Manager) AddRabbitmqConsumer(key string, rabbitmqConsumer IRabbitmqConsumer, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
 	manager.rabbitmqConsumers[key] = rabbitmqConsumer
+	manager.registryMux.Unlock()
 	manager.logger.Infof("consumer %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveRabbitmqConsumer ...
 func (manager *Manager) RemoveRabbitmqConsumer(key string) (IRabbitmqConsumer, error) {
+	if err := manager.stopRemoved(kindRabbitmqConsumer, key); err != nil {
+		return nil, err
+	}
+
+	manager.registryMux.Lock()
 	rabbitmqConsumer := manager.rabbitmqConsumers[key]
+	delete(manager.rabbitmqConsumers, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
 
-	delete(manager.processes, key)
 	manager.logger.Infof("consumer %s removed", key)
 
 	return rabbitmqConsumer, nil
@@ -34,7 +42,11 @@ func (manager *Manager) RemoveRabbitmqConsumer(key string) (IRabbitmqConsumer, e
 
 // GetRabbitmqConsumer ...
 func (manager *Manager) GetRabbitmqConsumer(key string) IRabbitmqConsumer {
-	if rabbitmqConsumer, exists := manager.rabbitmqConsumers[key]; exists {
+	manager.registryMux.RLock()
+	rabbitmqConsumer, exists := manager.rabbitmqConsumers[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return rabbitmqConsumer
 	}
 	manager.logger.Infof("consumer %s doesn't exist", key)
@@ -1,28 +1,37 @@
 package manager
 
-import "sync"
-
 // IRabbitmqProducer ...
 type IRabbitmqProducer interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	ILifecycle
 	Publish(routingKey string, body []byte, reliable bool) error
-	Started() bool
 }
 
 // AddRabbitmqProducer ...
-func (manager *Manager) AddRabbitmqProducer(key string, nsqProducer IRabbitmqProducer) error {
-	manager.rabbitmqProducers[key] = nsqProducer
+func (manager *Manager) AddRabbitmqProducer(key string, rabbitmqProducer IRabbitmqProducer, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
+	manager.rabbitmqProducers[key] = rabbitmqProducer
+	manager.registryMux.Unlock()
 	manager.logger.Infof("nsq producer %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveRabbitmqProducer ...
 func (manager *Manager) RemoveRabbitmqProducer(key string) (IRabbitmqProducer, error) {
+	if err := manager.stopRemoved(kindRabbitmqProducer, key); err != nil {
+		return nil, err
+	}
+
+	manager.registryMux.Lock()
 	process := manager.rabbitmqProducers[key]
+	delete(manager.rabbitmqProducers, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
 
-	delete(manager.processes, key)
 	manager.logger.Infof("nsq producer %s removed", key)
 
 	return process, nil
@@ -30,7 +39,11 @@ func (manager *Manager) RemoveRabbitmqProducer(key string) (IRabbitmqProducer, e
 
 // GetRabbitmqProducer ...
 func (manager *Manager) GetRabbitmqProducer(key string) IRabbitmqProducer {
-	if process, exists := manager.rabbitmqProducers[key]; exists {
+	manager.registryMux.RLock()
+	process, exists := manager.rabbitmqProducers[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return process
 	}
 	manager.logger.Infof("nsq producer %s doesn't exist", key)
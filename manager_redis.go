@@ -1,11 +1,7 @@
 package manager
 
-import "sync"
-
 type IRedis interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
-	Started() bool
+	ILifecycle
 
 	Action(command string, arguments ...string) error
 
@@ -78,10 +74,15 @@ type IRedis interface {
 	Publish(channel string, message []byte) (recieverCout int64, err error)
 }
 
+// IRedisSubscriber can be implemented by the redis that can subscribe the published messages
+type IRedisSubscriber interface {
+	Subscribe(channel string) (messages <-chan []byte, unsubscribe func() error, err error)
+}
+
 // RedisConfig ...
 type RedisConfig struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
+	Host     string `json:"host" validate:"required"`
+	Port     int    `json:"port" default:"6379" validate:"min=1,max=65535"`
 	Database int    `json:"database"`
 	Password string `json:"password"`
 }
@@ -97,18 +98,31 @@ func NewRedisConfig(host string, port int, database int, password string) *Redis
 }
 
 // AddRedis ...
-func (manager *Manager) AddRedis(key string, redis IRedis) error {
+func (manager *Manager) AddRedis(key string, redis IRedis, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
 	manager.redis[key] = redis
+	manager.registryMux.Unlock()
 	manager.logger.Infof("redis %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveRedis ...
 func (manager *Manager) RemoveRedis(key string) (IRedis, error) {
-	redis := manager.redis[key]
+	if err := manager.stopRemoved(kindRedis, key); err != nil {
+		return nil, err
+	}
 
+	manager.registryMux.Lock()
+	redis := manager.redis[key]
 	delete(manager.redis, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
+
 	manager.logger.Infof("redis %s removed", key)
 
 	return redis, nil
@@ -116,7 +130,11 @@ func (manager *Manager) RemoveRedis(key string) (IRedis, error) {
 
 // GetRedis ...
 func (manager *Manager) GetRedis(key string) interface{} {
-	if redis, exists := manager.redis[key]; exists {
+	manager.registryMux.RLock()
+	redis, exists := manager.redis[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return redis
 	}
 	manager.logger.Infof("redis %s doesn't exist", key)
@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+)
+
+// startAdded starts a component added while the manager is running,
+// its explicit dependencies must be already running
+func (manager *Manager) startAdded(key string) error {
+	if !manager.Started() {
+		return nil
+	}
+
+	g, err := manager.buildGraph()
+	if err != nil {
+		// the component is removed again, so that the graph of the other components can still be built to stop them
+		manager.registryMux.Lock()
+		manager.unregister(key)
+		manager.registryMux.Unlock()
+
+		manager.logger.Error(err)
+		return err
+	}
+
+	n := g.index[key]
+	for _, dependency := range n.config.dependsOn {
+		if state := g.index[dependency].config.state.State(); state != StateRunning {
+			// the component is removed again, so that it can be added when its dependency is running
+			manager.registryMux.Lock()
+			manager.unregister(key)
+			manager.registryMux.Unlock()
+
+			return &ComponentError{
+				Key:    n.key,
+				Kind:   n.kind,
+				Action: "start",
+				Err:    fmt.Errorf("dependency %s isn't running [ state: %s ]", dependency, state),
+			}
+		}
+	}
+
+	return manager.startComponent(context.Background(), n)
+}
+
+// stopRemoved stops gracefully a component of the kind that is going to be removed while the manager is running.
+// a component can't be removed while other components depend on it, or when the key isn't of a component of the kind
+func (manager *Manager) stopRemoved(kind, key string) error {
+	var removed *node
+	kinds := manager.kinds()
+
+	for _, current := range kinds {
+		for _, n := range current {
+			if n.key == key && n.kind == kind {
+				removed = n
+			}
+		}
+	}
+
+	if removed == nil {
+		return &ComponentError{Key: key, Kind: kind, Action: "remove", Err: ErrComponentNotFound}
+	}
+
+	for _, current := range kinds {
+		for _, n := range current {
+			for _, dependency := range n.config.dependsOn {
+				if dependency == key {
+					return fmt.Errorf("component can't be removed while other components depend on it [ component: %s, dependent: %s ]", key, n.key)
+				}
+			}
+		}
+	}
+
+	if !manager.Started() {
+		return nil
+	}
+
+	ctx, cancel := manager.shutdownContext()
+	defer cancel()
+
+	return manager.stopComponent(ctx, removed)
+}
+
+// unregister removes the component of any kind, must be called holding the registry lock
+func (manager *Manager) unregister(key string) {
+	delete(manager.featureFlags, key)
+	delete(manager.dbs, key)
+	delete(manager.nsqProducers, key)
+	delete(manager.nsqConsumers, key)
+	delete(manager.rabbitmqProducers, key)
+	delete(manager.rabbitmqConsumers, key)
+	delete(manager.redis, key)
+	delete(manager.worklist, key)
+	delete(manager.processes, key)
+	delete(manager.webs, key)
+	manager.removeComponent(key)
+}
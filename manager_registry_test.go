@@ -0,0 +1,250 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// fakeEvents records the starts and the stops of the components, that can run in parallel
+type fakeEvents struct {
+	events []string
+	mux    sync.Mutex
+}
+
+func (events *fakeEvents) add(event string) {
+	events.mux.Lock()
+	defer events.mux.Unlock()
+
+	events.events = append(events.events, event)
+}
+
+// index returns the position of the event, -1 when it didn't happen
+func (events *fakeEvents) index(event string) int {
+	events.mux.Lock()
+	defer events.mux.Unlock()
+
+	for i, current := range events.events {
+		if current == event {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// fakeComponent is a lifecycle component that records the starts and the stops,
+// failing the first starts (the failures) or all of them (with the start error)
+type fakeComponent struct {
+	name     string
+	startErr error
+	stopErr  error
+	failures int
+	starts   int
+	stops    int
+	events   *fakeEvents
+	state    *StateMachine
+	mux      sync.Mutex
+}
+
+func newFakeComponent(name string, events *fakeEvents) *fakeComponent {
+	return &fakeComponent{
+		name:   name,
+		events: events,
+		state:  NewStateMachine(),
+	}
+}
+
+func (component *fakeComponent) Start(ctx context.Context) (err error) {
+	if !component.state.start() {
+		return nil
+	}
+	defer func() { component.state.started(err) }()
+
+	component.mux.Lock()
+	defer component.mux.Unlock()
+
+	component.starts++
+	if component.events != nil {
+		component.events.add("start " + component.name)
+	}
+
+	if component.starts <= component.failures {
+		return errors.New("start failed")
+	}
+
+	return component.startErr
+}
+
+func (component *fakeComponent) Stop(ctx context.Context) (err error) {
+	if !component.state.stop() {
+		return nil
+	}
+	defer func() { component.state.stopped(err) }()
+
+	component.mux.Lock()
+	defer component.mux.Unlock()
+
+	component.stops++
+	if component.events != nil {
+		component.events.add("stop " + component.name)
+	}
+
+	return component.stopErr
+}
+
+func (component *fakeComponent) Started() bool {
+	return component.state.Started()
+}
+
+// counts returns the starts and the stops
+func (component *fakeComponent) counts() (int, int) {
+	component.mux.Lock()
+	defer component.mux.Unlock()
+
+	return component.starts, component.stops
+}
+
+// fakeWorkList is a work list made of a fake component
+type fakeWorkList struct {
+	*fakeComponent
+}
+
+func (list *fakeWorkList) AddWork(id string, work interface{}) error {
+	return nil
+}
+
+func startedManager(t *testing.T) *Manager {
+	m := NewManager(WithRunInBackground(true))
+	if err := m.Start(); err != nil {
+		t.Fatalf("error starting the manager: %s", err)
+	}
+	t.Cleanup(func() { m.Stop() })
+
+	return m
+}
+
+func TestRemoveOnlyFromTheRegistryOfTheKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		started bool
+	}{
+		{name: "manager stopped"},
+		{name: "manager running", started: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager(WithRunInBackground(true))
+			if test.started {
+				m = startedManager(t)
+			}
+
+			list := &fakeWorkList{newFakeComponent("wl", nil)}
+			if err := m.AddWorkList("wl", list); err != nil {
+				t.Fatalf("error adding the work list: %s", err)
+			}
+
+			process, err := m.RemoveProcess("wl")
+			if !errors.Is(err, ErrComponentNotFound) {
+				t.Errorf("removing the process got the error %v, expected %s", err, ErrComponentNotFound)
+			}
+
+			if process != nil {
+				t.Errorf("removed the process %v, expected none", process)
+			}
+
+			if m.GetWorkList("wl") == nil || m.State("wl") == nil {
+				t.Errorf("the work list was removed by the removal of a process")
+			}
+
+			if list.stops != 0 {
+				t.Errorf("the work list was stopped %d times, expected 0", list.stops)
+			}
+
+			if _, err := m.RemoveWorkList("wl"); err != nil {
+				t.Fatalf("error removing the work list: %s", err)
+			}
+
+			if m.GetWorkList("wl") != nil || m.State("wl") != nil {
+				t.Errorf("the work list wasn't removed")
+			}
+		})
+	}
+}
+
+func TestAddAndRemoveWhileRunning(t *testing.T) {
+	m := startedManager(t)
+
+	process := newFakeComponent("process", nil)
+	if err := m.AddProcess("process", process); err != nil {
+		t.Fatalf("error adding the process: %s", err)
+	}
+
+	if !process.Started() || m.State("process").State != StateRunning {
+		t.Fatalf("the process added while running wasn't started")
+	}
+
+	dependent := newFakeComponent("dependent", nil)
+	if err := m.AddProcess("dependent", dependent, DependsOn("process")); err != nil {
+		t.Fatalf("error adding the dependent process: %s", err)
+	}
+
+	if _, err := m.RemoveProcess("process"); err == nil {
+		t.Errorf("removed the process while other process depends on it")
+	}
+
+	if _, err := m.RemoveProcess("dependent"); err != nil {
+		t.Fatalf("error removing the dependent process: %s", err)
+	}
+
+	if _, err := m.RemoveProcess("process"); err != nil {
+		t.Fatalf("error removing the process: %s", err)
+	}
+
+	if process.Started() || process.stops != 1 {
+		t.Errorf("the process removed while running was stopped %d times, expected 1", process.stops)
+	}
+}
+
+func TestAddWithADependencyNotRunning(t *testing.T) {
+	m := startedManager(t)
+
+	failing := newFakeComponent("failing", nil)
+	failing.startErr = errors.New("start failed")
+	if err := m.AddProcess("failing", failing); err == nil {
+		t.Fatalf("added the failing process without an error")
+	}
+
+	process := newFakeComponent("process", nil)
+	if err := m.AddProcess("process", process, DependsOn("failing")); err == nil {
+		t.Fatalf("added the process without an error, its dependency isn't running")
+	}
+
+	if m.GetProcess("process") != nil || m.State("process") != nil {
+		t.Fatalf("the process with the dependency not running is still registered")
+	}
+
+	if err := m.AddProcess("process", process); err != nil {
+		t.Fatalf("error adding the process again: %s", err)
+	}
+
+	if !process.Started() {
+		t.Errorf("the process added again wasn't started")
+	}
+}
+
+func TestAddWhileStarting(t *testing.T) {
+	m := NewManager(WithRunInBackground(true))
+	m.state.Transition(StateStarting, nil)
+
+	err := m.AddProcess("process", newFakeComponent("process", nil))
+	if !errors.Is(err, ErrManagerStarting) {
+		t.Errorf("adding while starting got the error %v, expected %s", err, ErrManagerStarting)
+	}
+
+	if m.GetProcess("process") != nil {
+		t.Errorf("the process added while starting is registered")
+	}
+}
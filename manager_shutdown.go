@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// ExitCodeOK when the manager stopped cleanly
+	ExitCodeOK = 0
+	// ExitCodeError when the manager failed to start or some component failed to stop
+	ExitCodeError = 1
+	// ExitCodeForcedStop when some component didn't stop in time and was abandoned
+	ExitCodeForcedStop = 2
+)
+
+// ErrStopTimeout ...
+var ErrStopTimeout = errors.New("stop timeout exceeded")
+
+// WithStopTimeout overrides the shutdown timeout of the manager for this component
+func WithStopTimeout(timeout time.Duration) ComponentOption {
+	return func(config *componentConfig) {
+		config.stopTimeout = timeout
+	}
+}
+
+// ExitCode returns the exit code that reflects the error returned by the Start or Stop of the manager
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitCodeOK
+	case errors.Is(err, ErrStopTimeout):
+		return ExitCodeForcedStop
+	default:
+		return ExitCodeError
+	}
+}
+
+// shutdownContext ...
+func (manager *Manager) shutdownContext() (context.Context, context.CancelFunc) {
+	if manager.shutdownTimeout > 0 {
+		return context.WithTimeout(context.Background(), manager.shutdownTimeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
+
+// stopComponent stops the component without waiting beyond its deadline,
+// a component that doesn't stop in time is abandoned and reported with ErrStopTimeout
+func (manager *Manager) stopComponent(ctx context.Context, n *node) error {
+	manager.unsupervise(n.key)
+
+	if n.config.stopTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.config.stopTimeout)
+		defer cancel()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- manager.executeAction(ctx, "stop", n)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+		if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+	case <-ctx.Done():
+	}
+
+	manager.logger.Errorf("component didn't stop in time, forcing [ %s: %s ]", n.kind, n.key)
+	n.config.state.Transition(StateFailed, ErrStopTimeout)
+
+	return &ComponentError{Key: n.key, Kind: n.kind, Action: "stop", Err: ErrStopTimeout}
+}
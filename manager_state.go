@@ -0,0 +1,179 @@
+package manager
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State ...
+type State string
+
+const (
+	// StateCreated the component was added and never started
+	StateCreated State = "created"
+	// StateStarting the component is starting
+	StateStarting State = "starting"
+	// StateRunning the component started successfully
+	StateRunning State = "running"
+	// StateStopping the component is stopping
+	StateStopping State = "stopping"
+	// StateStopped the component stopped successfully
+	StateStopped State = "stopped"
+	// StateFailed the component failed to start or to stop, or it exited with an error
+	StateFailed State = "failed"
+)
+
+// transitions allowed between the states
+var transitions = map[State][]State{
+	StateCreated:  {StateStarting},
+	StateStarting: {StateRunning, StateFailed},
+	StateRunning:  {StateStopping, StateStopped, StateFailed},
+	StateStopping: {StateStopped, StateFailed},
+	StateStopped:  {StateStarting},
+	StateFailed:   {StateStarting, StateStopping},
+}
+
+// StateInfo ...
+type StateInfo struct {
+	State     State     `json:"state"`
+	Since     time.Time `json:"since"`
+	LastError error     `json:"-"`
+}
+
+// StateError ...
+type StateError struct {
+	From State
+	To   State
+}
+
+// Error ...
+func (e *StateError) Error() string {
+	return fmt.Sprintf("invalid state transition [ from: %s, to: %s ]", e.From, e.To)
+}
+
+// StateMachine keeps the lifecycle state, only allowing the valid transitions
+type StateMachine struct {
+	state     State
+	since     time.Time
+	lastError error
+	mux       sync.Mutex
+}
+
+// NewStateMachine creates a state machine on the created state
+func NewStateMachine() *StateMachine {
+	return &StateMachine{
+		state: StateCreated,
+		since: time.Now(),
+	}
+}
+
+// Transition changes to the state when the transition is valid, otherwise it returns a *StateError.
+// the error is kept as the last error of the state machine
+func (machine *StateMachine) Transition(to State, err error) error {
+	machine.mux.Lock()
+	defer machine.mux.Unlock()
+
+	if !machine.canTransition(to) {
+		return &StateError{From: machine.state, To: to}
+	}
+
+	machine.state = to
+	machine.since = time.Now()
+	if err != nil {
+		machine.lastError = err
+	}
+
+	return nil
+}
+
+// State ...
+func (machine *StateMachine) State() State {
+	machine.mux.Lock()
+	defer machine.mux.Unlock()
+
+	return machine.state
+}
+
+// Info returns a snapshot of the state
+func (machine *StateMachine) Info() *StateInfo {
+	machine.mux.Lock()
+	defer machine.mux.Unlock()
+
+	return &StateInfo{
+		State:     machine.state,
+		Since:     machine.since,
+		LastError: machine.lastError,
+	}
+}
+
+// Started checks if the state is running
+func (machine *StateMachine) Started() bool {
+	return machine.State() == StateRunning
+}
+
+// start changes to the starting state, returning false when it is already starting, running or stopping
+func (machine *StateMachine) start() bool {
+	return machine.Transition(StateStarting, nil) == nil
+}
+
+// started ends the start on the running state or, with the error, on the failed state
+func (machine *StateMachine) started(err error) {
+	if err != nil {
+		machine.Transition(StateFailed, err)
+		return
+	}
+
+	machine.Transition(StateRunning, nil)
+}
+
+// stop changes to the stopping state, returning false when it isn't running
+// (ex: the start failed, so there is nothing to stop)
+func (machine *StateMachine) stop() bool {
+	machine.mux.Lock()
+	defer machine.mux.Unlock()
+
+	if machine.state != StateRunning {
+		return false
+	}
+
+	machine.state = StateStopping
+	machine.since = time.Now()
+
+	return true
+}
+
+// stopped ends the stop on the stopped state or, with the error, on the failed state
+func (machine *StateMachine) stopped(err error) {
+	if err != nil {
+		machine.Transition(StateFailed, err)
+		return
+	}
+
+	machine.Transition(StateStopped, nil)
+}
+
+// canTransition ...
+func (machine *StateMachine) canTransition(to State) bool {
+	for _, state := range transitions[machine.state] {
+		if state == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// State returns the state of the component, nil when it doesn't exist
+func (manager *Manager) State(key string) *StateInfo {
+	manager.registryMux.RLock()
+	config, exists := manager.components[key]
+	manager.registryMux.RUnlock()
+
+	if !exists {
+		manager.logger.Infof("component %s doesn't exist", key)
+		return nil
+	}
+
+	return config.state.Info()
+}
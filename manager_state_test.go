@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStateMachineTransitions(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []State
+		to    State
+		valid bool
+	}{
+		{name: "created to starting", to: StateStarting, valid: true},
+		{name: "created to running", to: StateRunning},
+		{name: "created to stopping", to: StateStopping},
+		{name: "starting to running", path: []State{StateStarting}, to: StateRunning, valid: true},
+		{name: "starting to failed", path: []State{StateStarting}, to: StateFailed, valid: true},
+		{name: "starting to stopped", path: []State{StateStarting}, to: StateStopped},
+		{name: "running to stopping", path: []State{StateStarting, StateRunning}, to: StateStopping, valid: true},
+		{name: "running to stopped", path: []State{StateStarting, StateRunning}, to: StateStopped, valid: true},
+		{name: "running to starting", path: []State{StateStarting, StateRunning}, to: StateStarting},
+		{name: "stopping to stopped", path: []State{StateStarting, StateRunning, StateStopping}, to: StateStopped, valid: true},
+		{name: "stopping to running", path: []State{StateStarting, StateRunning, StateStopping}, to: StateRunning},
+		{name: "stopped to starting", path: []State{StateStarting, StateRunning, StateStopped}, to: StateStarting, valid: true},
+		{name: "stopped to running", path: []State{StateStarting, StateRunning, StateStopped}, to: StateRunning},
+		{name: "failed to starting", path: []State{StateStarting, StateFailed}, to: StateStarting, valid: true},
+		{name: "failed to stopping", path: []State{StateStarting, StateFailed}, to: StateStopping, valid: true},
+		{name: "failed to running", path: []State{StateStarting, StateFailed}, to: StateRunning},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			machine := NewStateMachine()
+			for _, state := range test.path {
+				if err := machine.Transition(state, nil); err != nil {
+					t.Fatalf("error on the path to the state: %s", err)
+				}
+			}
+			from := machine.State()
+
+			err := machine.Transition(test.to, nil)
+			if test.valid {
+				if err != nil || machine.State() != test.to {
+					t.Errorf("the transition failed with %v, the state is %s", err, machine.State())
+				}
+				return
+			}
+
+			var stateErr *StateError
+			if !errors.As(err, &stateErr) || stateErr.From != from || stateErr.To != test.to {
+				t.Errorf("the transition got the error %v, expected a *StateError from %s to %s", err, from, test.to)
+			}
+
+			if machine.State() != from {
+				t.Errorf("the state changed to %s on an invalid transition", machine.State())
+			}
+		})
+	}
+}
+
+func TestStateMachineLifecycle(t *testing.T) {
+	machine := NewStateMachine()
+
+	if machine.stop() {
+		t.Errorf("stopped a state machine that never started")
+	}
+
+	startErr := errors.New("start failed")
+	if !machine.start() {
+		t.Fatalf("the state machine didn't start")
+	}
+	machine.started(startErr)
+
+	if info := machine.Info(); info.State != StateFailed || info.LastError != startErr {
+		t.Errorf("the failed start is %s with the error %v, expected %s with %s", info.State, info.LastError, StateFailed, startErr)
+	}
+
+	if machine.stop() {
+		t.Errorf("stopped a state machine that failed to start")
+	}
+
+	if !machine.start() {
+		t.Fatalf("the state machine didn't start again after failing")
+	}
+
+	if machine.start() {
+		t.Errorf("started a state machine that is already starting")
+	}
+	machine.started(nil)
+
+	if !machine.Started() || machine.start() {
+		t.Errorf("the state machine is %s, expected running and not to start again", machine.State())
+	}
+
+	if !machine.stop() || machine.stop() {
+		t.Errorf("the running state machine didn't stop only once")
+	}
+	machine.stopped(nil)
+
+	if info := machine.Info(); info.State != StateStopped || info.LastError != startErr {
+		t.Errorf("the stop is %s with the error %v, expected %s keeping the last error", info.State, info.LastError, StateStopped)
+	}
+}
+
+func TestManagerStates(t *testing.T) {
+	m := NewManager(WithRunInBackground(true))
+	m.AddProcess("process", newFakeComponent("process", nil))
+
+	if state := m.State("process").State; state != StateCreated {
+		t.Errorf("the added process is %s, expected %s", state, StateCreated)
+	}
+
+	if m.State("missing") != nil {
+		t.Errorf("got the state of a component that doesn't exist")
+	}
+
+	if err := m.Start(); err != nil {
+		t.Fatalf("error starting the manager: %s", err)
+	}
+
+	if state := m.State("process").State; !m.Started() || state != StateRunning {
+		t.Errorf("the manager is %s and the process %s, expected both running", m.state.State(), state)
+	}
+
+	if err := m.Stop(); err != nil {
+		t.Fatalf("error stopping the manager: %s", err)
+	}
+
+	if state := m.State("process").State; m.state.State() != StateStopped || state != StateStopped {
+		t.Errorf("the manager is %s and the process %s, expected both stopped", m.state.State(), state)
+	}
+
+	if err := m.Start(); err != nil || !m.Started() {
+		t.Fatalf("the manager didn't start again: %v", err)
+	}
+	m.Stop()
+}
@@ -0,0 +1,59 @@
+package manager
+
+import "time"
+
+// ComponentStatus ...
+type ComponentStatus struct {
+	Key       string     `json:"key"`
+	Kind      string     `json:"kind"`
+	Started   bool       `json:"started"`
+	State     State      `json:"state"`
+	Since     time.Time  `json:"since"`
+	Error     string     `json:"error,omitempty"`
+	DependsOn []string   `json:"depends_on,omitempty"`
+	NextRun   *time.Time `json:"next_run,omitempty"`
+}
+
+// ManagerStatus ...
+type ManagerStatus struct {
+	Started    bool               `json:"started"`
+	State      State              `json:"state"`
+	Components []*ComponentStatus `json:"components"`
+}
+
+// Status returns a snapshot of the status of the manager and of its components
+func (manager *Manager) Status() *ManagerStatus {
+	status := &ManagerStatus{
+		Started:    manager.Started(),
+		State:      manager.state.State(),
+		Components: make([]*ComponentStatus, 0),
+	}
+
+	for _, kind := range manager.kinds() {
+		for _, n := range kind {
+			state := n.config.state.Info()
+			componentStatus := &ComponentStatus{
+				Key:       n.key,
+				Kind:      n.kind,
+				Started:   n.component.Started(),
+				State:     state.State,
+				Since:     state.Since,
+				DependsOn: n.config.dependsOn,
+			}
+
+			if state.LastError != nil {
+				componentStatus.Error = state.LastError.Error()
+			}
+
+			if scheduled, ok := n.component.(IScheduled); ok {
+				if nextRun := scheduled.NextRun(); !nextRun.IsZero() {
+					componentStatus.NextRun = &nextRun
+				}
+			}
+
+			status.Components = append(status.Components, componentStatus)
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,247 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RestartPolicy ...
+type RestartPolicy string
+
+const (
+	// RestartNever never restarts the component
+	RestartNever RestartPolicy = "never"
+	// RestartOnFailure restarts the component when it fails to start or exits with an error
+	RestartOnFailure RestartPolicy = "on-failure"
+	// RestartAlways restarts the component whenever it fails or exits
+	RestartAlways RestartPolicy = "always"
+)
+
+// IExitNotifier can be implemented by the components that can end on their own,
+// the channel receives the result of each run that ended without being stopped
+type IExitNotifier interface {
+	Exited() <-chan error
+}
+
+// RestartConfig ...
+type RestartConfig struct {
+	Policy      RestartPolicy `json:"policy"`
+	MaxRestarts int           `json:"max_restarts"`
+	Window      time.Duration `json:"window"`
+	MinBackoff  time.Duration `json:"min_backoff"`
+	MaxBackoff  time.Duration `json:"max_backoff"`
+	Jitter      float64       `json:"jitter"`
+}
+
+// NewRestartConfig creates a restart configuration that allows maxRestarts restarts
+// in the window (0 means unlimited) with an exponential backoff between 1 and 30 seconds
+func NewRestartConfig(policy RestartPolicy, maxRestarts int, window time.Duration) *RestartConfig {
+	return &RestartConfig{
+		Policy:      policy,
+		MaxRestarts: maxRestarts,
+		Window:      window,
+		MinBackoff:  time.Second,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// WithRestartPolicy supervises the component with the restart configuration.
+// when the restarts exceed the limit, the whole manager is stopped
+func WithRestartPolicy(config *RestartConfig) ComponentOption {
+	return func(c *componentConfig) {
+		c.restart = config
+	}
+}
+
+// backoff returns the time to wait before the attempt, exponential with jitter
+func (config *RestartConfig) backoff(attempt int) time.Duration {
+	backoff := config.MinBackoff
+	for i := 0; i < attempt && backoff < config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if config.MaxBackoff > 0 && backoff > config.MaxBackoff {
+		backoff = config.MaxBackoff
+	}
+
+	if config.Jitter > 0 {
+		delta := float64(backoff) * config.Jitter
+		backoff += time.Duration(delta*2*rand.Float64() - delta)
+	}
+
+	return backoff
+}
+
+// supervisor ...
+type supervisor struct {
+	manager  *Manager
+	node     *node
+	config   *RestartConfig
+	restarts []time.Time
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mux      sync.Mutex
+}
+
+// startComponent starts the component, under supervision when it has a restart policy
+func (manager *Manager) startComponent(ctx context.Context, n *node) error {
+	if n.config.restart == nil || n.config.restart.Policy == RestartNever || n.config.restart.Policy == "" {
+		return manager.executeAction(ctx, "start", n)
+	}
+
+	supervisorCtx, cancel := context.WithCancel(context.Background())
+	sup := &supervisor{
+		manager: manager,
+		node:    n,
+		config:  n.config.restart,
+		ctx:     supervisorCtx,
+		cancel:  cancel,
+	}
+
+	if err := sup.start(ctx); err != nil {
+		cancel()
+		return err
+	}
+
+	manager.supervisorsMux.Lock()
+	manager.supervisors[n.key] = sup
+	manager.supervisorsMux.Unlock()
+
+	return nil
+}
+
+// unsupervise stops the supervision of the component, so that it isn't restarted while stopping
+func (manager *Manager) unsupervise(key string) {
+	manager.supervisorsMux.Lock()
+	defer manager.supervisorsMux.Unlock()
+
+	if sup, exists := manager.supervisors[key]; exists {
+		sup.cancel()
+		delete(manager.supervisors, key)
+	}
+}
+
+// start starts the component retrying the failures allowed by the policy, and then watches it
+func (sup *supervisor) start(ctx context.Context) error {
+	for {
+		err := sup.manager.executeAction(ctx, "start", sup.node)
+		if err == nil {
+			break
+		}
+
+		if ctx.Err() != nil {
+			return err
+		}
+
+		if retryErr := sup.wait(ctx, err); retryErr != nil {
+			return retryErr
+		}
+	}
+
+	if notifier, ok := sup.node.component.(IExitNotifier); ok {
+		go sup.watch(notifier)
+	}
+
+	return nil
+}
+
+// watch restarts the component when it exits, as allowed by the policy
+func (sup *supervisor) watch(notifier IExitNotifier) {
+	select {
+	case <-sup.ctx.Done():
+		return
+	case err := <-notifier.Exited():
+		if sup.ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			sup.node.config.state.Transition(StateStopped, nil)
+		} else {
+			sup.node.config.state.Transition(StateFailed, err)
+		}
+
+		if err == nil && sup.config.Policy != RestartAlways {
+			sup.manager.logger.Infof("component exited [ %s: %s ]", sup.node.kind, sup.node.key)
+			return
+		}
+
+		sup.manager.logger.Warnf("component exited [ %s: %s ]: %v", sup.node.kind, sup.node.key, err)
+
+		if retryErr := sup.wait(sup.ctx, err); retryErr != nil {
+			if sup.ctx.Err() == nil {
+				sup.manager.escalate(retryErr)
+			}
+			return
+		}
+
+		// releases what the component still holds, it is already stopped or failed
+		if sup.node.component.Started() {
+			if err := sup.node.component.Stop(sup.ctx); err != nil {
+				sup.manager.logger.Errorf("error stopping component before restart [ %s: %s ]: %s", sup.node.kind, sup.node.key, err)
+			}
+		}
+
+		if err := sup.start(sup.ctx); err != nil {
+			if sup.ctx.Err() == nil {
+				sup.manager.escalate(err)
+			}
+			return
+		}
+
+		sup.manager.logger.Infof("component restarted [ %s: %s ]", sup.node.kind, sup.node.key)
+	}
+}
+
+// wait registers a restart and waits for its backoff, it fails when the restarts exceed the limit
+func (sup *supervisor) wait(ctx context.Context, cause error) error {
+	sup.mux.Lock()
+	now := time.Now()
+
+	restarts := sup.restarts[:0]
+	for _, restart := range sup.restarts {
+		if sup.config.Window <= 0 || now.Sub(restart) < sup.config.Window {
+			restarts = append(restarts, restart)
+		}
+	}
+	sup.restarts = restarts
+
+	if sup.config.MaxRestarts > 0 && len(sup.restarts) >= sup.config.MaxRestarts {
+		sup.mux.Unlock()
+
+		err := fmt.Errorf("exceeded %d restarts in %s", sup.config.MaxRestarts, sup.config.Window)
+		if cause != nil {
+			err = fmt.Errorf("%s: %w", err, cause)
+		}
+
+		return &ComponentError{Key: sup.node.key, Kind: sup.node.kind, Action: "restart", Err: err}
+	}
+
+	attempt := len(sup.restarts)
+	sup.restarts = append(sup.restarts, now)
+	sup.mux.Unlock()
+
+	backoff := sup.config.backoff(attempt)
+	sup.manager.logger.Warnf("restarting component in %s [ %s: %s, restart: %d ]", backoff, sup.node.kind, sup.node.key, attempt+1)
+
+	select {
+	case <-time.After(backoff):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// escalate stops the whole manager because a component couldn't be kept running
+func (manager *Manager) escalate(err error) {
+	manager.logger.Errorf("escalating, stopping the manager: %s", err)
+
+	select {
+	case manager.escalation <- err:
+	default:
+	}
+}
@@ -0,0 +1,167 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeExitingComponent is a fake component that can end on its own, with the error sent to exit
+type fakeExitingComponent struct {
+	*fakeComponent
+	exit chan error
+}
+
+func newFakeExitingComponent(name string) *fakeExitingComponent {
+	return &fakeExitingComponent{
+		fakeComponent: newFakeComponent(name, nil),
+		exit:          make(chan error),
+	}
+}
+
+func (component *fakeExitingComponent) Exited() <-chan error {
+	return component.exit
+}
+
+// fastRestarts restarts without waiting, allowing the max restarts in a minute
+func fastRestarts(policy RestartPolicy, maxRestarts int) *RestartConfig {
+	config := NewRestartConfig(policy, maxRestarts, time.Minute)
+	config.MinBackoff = time.Millisecond
+	config.MaxBackoff = time.Millisecond
+	config.Jitter = 0
+
+	return config
+}
+
+// eventually waits until the condition is true
+func eventually(t *testing.T, condition func() bool, message string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRestartConfigBackoff(t *testing.T) {
+	config := &RestartConfig{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 0, expected: time.Second},
+		{attempt: 1, expected: 2 * time.Second},
+		{attempt: 3, expected: 8 * time.Second},
+		{attempt: 4, expected: 10 * time.Second},
+		{attempt: 10, expected: 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		if backoff := config.backoff(test.attempt); backoff != test.expected {
+			t.Errorf("the backoff of the attempt %d is %s, expected %s", test.attempt, backoff, test.expected)
+		}
+	}
+}
+
+func TestSupervisorStartRetries(t *testing.T) {
+	tests := []struct {
+		name           string
+		failures       int
+		maxRestarts    int
+		expectedStarts int
+		expectedErr    bool
+	}{
+		{name: "started after the failures", failures: 2, maxRestarts: 3, expectedStarts: 3},
+		{name: "failures over the max restarts", failures: 5, maxRestarts: 2, expectedStarts: 3, expectedErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager(WithRunInBackground(true))
+			t.Cleanup(func() { m.Stop() })
+
+			process := newFakeComponent("process", nil)
+			process.failures = test.failures
+			m.AddProcess("process", process, WithRestartPolicy(fastRestarts(RestartOnFailure, test.maxRestarts)))
+
+			if err := m.Start(); (err != nil) != test.expectedErr {
+				t.Fatalf("starting got the error %v, expected an error: %t", err, test.expectedErr)
+			}
+
+			if starts, _ := process.counts(); starts != test.expectedStarts {
+				t.Errorf("the process was started %d times, expected %d", starts, test.expectedStarts)
+			}
+		})
+	}
+}
+
+func TestSupervisorRestartsOnExit(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         RestartPolicy
+		exitErr        error
+		expectedStarts int
+		expectedState  State
+	}{
+		{name: "on failure restarts on error", policy: RestartOnFailure, exitErr: errors.New("crashed"), expectedStarts: 2, expectedState: StateRunning},
+		{name: "on failure doesn't restart on success", policy: RestartOnFailure, expectedStarts: 1, expectedState: StateStopped},
+		{name: "always restarts on success", policy: RestartAlways, expectedStarts: 2, expectedState: StateRunning},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager(WithRunInBackground(true))
+			t.Cleanup(func() { m.Stop() })
+
+			process := newFakeExitingComponent("process")
+			m.AddProcess("process", process, WithRestartPolicy(fastRestarts(test.policy, 3)))
+
+			if err := m.Start(); err != nil {
+				t.Fatalf("error starting the manager: %s", err)
+			}
+
+			process.exit <- test.exitErr
+
+			eventually(t, func() bool {
+				starts, _ := process.counts()
+				return starts == test.expectedStarts && m.State("process").State == test.expectedState
+			}, "the process wasn't supervised as expected")
+
+			if !m.Started() {
+				t.Errorf("the manager stopped, expected to keep running")
+			}
+		})
+	}
+}
+
+func TestSupervisorEscalation(t *testing.T) {
+	m := NewManager(WithRunInBackground(true))
+	t.Cleanup(func() { m.Stop() })
+
+	process := newFakeExitingComponent("process")
+	other := newFakeComponent("other", nil)
+	m.AddProcess("process", process, WithRestartPolicy(fastRestarts(RestartOnFailure, 1)))
+	m.AddProcess("other", other)
+
+	if err := m.Start(); err != nil {
+		t.Fatalf("error starting the manager: %s", err)
+	}
+
+	// the first exit is restarted, the second one exceeds the max restarts
+	process.exit <- errors.New("crashed")
+	eventually(t, func() bool {
+		starts, _ := process.counts()
+		return starts == 2 && m.State("process").State == StateRunning
+	}, "the process wasn't restarted")
+
+	process.exit <- errors.New("crashed again")
+	eventually(t, func() bool { return m.state.State() == StateStopped }, "the manager wasn't stopped by the escalation")
+
+	if _, stops := other.counts(); stops != 1 {
+		t.Errorf("the other process was stopped %d times, expected 1", stops)
+	}
+}
@@ -1,7 +1,5 @@
 package manager
 
-import "sync"
-
 type HandlerFunc interface{}
 type MiddlewareFunc interface{}
 type Route struct {
@@ -21,25 +19,36 @@ type IWeb interface {
 	AddRoutes(routes ...*Route) error
 	AddNamespace(path string, middleware []MiddlewareFunc, routes ...*Route) error
 	AddFilter(pattern string, position string, middleware MiddlewareFunc, method string, methods ...string)
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
-	Started() bool
+	ILifecycle
 	GetClient() interface{}
 }
 
 // AddWeb ...
-func (manager *Manager) AddWeb(key string, web IWeb) error {
+func (manager *Manager) AddWeb(key string, web IWeb, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
 	manager.webs[key] = web
+	manager.registryMux.Unlock()
 	manager.logger.Infof("web %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveWeb ...
 func (manager *Manager) RemoveWeb(key string) (IWeb, error) {
-	web := manager.webs[key]
+	if err := manager.stopRemoved(kindWeb, key); err != nil {
+		return nil, err
+	}
 
+	manager.registryMux.Lock()
+	web := manager.webs[key]
 	delete(manager.webs, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
+
 	manager.logger.Infof("web %s removed", key)
 
 	return web, nil
@@ -47,7 +56,11 @@ func (manager *Manager) RemoveWeb(key string) (IWeb, error) {
 
 // GetWeb ...
 func (manager *Manager) GetWeb(key string) IWeb {
-	if web, ok := manager.webs[key]; ok {
+	manager.registryMux.RLock()
+	web, ok := manager.webs[key]
+	manager.registryMux.RUnlock()
+
+	if ok {
 		return web
 	}
 	manager.logger.Infof("web %s doesn't exist", key)
@@ -1,24 +1,28 @@
 package manager
 
 import (
-	"sync"
+	"fmt"
 	"time"
 )
 
 type IWorkList interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
-	Started() bool
-	AddWork(id string, work interface{})
+	ILifecycle
+	AddWork(id string, work interface{}) error
+}
+
+// IScalableWorkList can be implemented by the work lists that can change the number of workers while running
+type IScalableWorkList interface {
+	SetMaxWorkers(maxWorkers int) error
 }
 
 // WorkListConfig ...
 type WorkListConfig struct {
 	Name       string        `json:"name"`
-	MaxWorkers int           `json:"max_workers"`
-	MaxRetries int           `json:"max_retries"`
-	SleepTime  time.Duration `json:"sleep_time"`
+	MaxWorkers int           `json:"max_workers" default:"1" validate:"min=1"`
+	MaxRetries int           `json:"max_retries" validate:"min=0"`
+	SleepTime  time.Duration `json:"sleep_time" default:"1s" validate:"min=1ms"`
 	Mode       Mode          `json:"mode"`
+	WorkListStorageConfig
 }
 
 // NewWorkListConfig...
@@ -35,11 +39,12 @@ func NewWorkListConfig(name string, maxWorkers, maxRetries int, sleepTime time.D
 // BulkWorkListConfig ...
 type BulkWorkListConfig struct {
 	Name       string        `json:"name"`
-	MaxWorks   int           `json:"max_works"`
-	MaxWorkers int           `json:"max_workers"`
-	MaxRetries int           `json:"max_retries"`
-	SleepTime  time.Duration `json:"sleep_time"`
+	MaxWorks   int           `json:"max_works" default:"1" validate:"min=1"`
+	MaxWorkers int           `json:"max_workers" default:"1" validate:"min=1"`
+	MaxRetries int           `json:"max_retries" validate:"min=0"`
+	SleepTime  time.Duration `json:"sleep_time" default:"1s" validate:"min=1ms"`
 	Mode       Mode          `json:"mode"`
+	WorkListStorageConfig
 }
 
 // NewBulkWorkListConfig...
@@ -54,19 +59,109 @@ func NewBulkWorkListConfig(name string, maxWorks, maxWorkers, maxRetries int, sl
 	}
 }
 
+// WorkListStorageConfig selects where the works of a work list are kept, only in memory by default
+type WorkListStorageConfig struct {
+	// Persistence is the directory of the write ahead log of the works
+	Persistence string `json:"persistence,omitempty"`
+	// Redis is the key of the redis of the manager where the works are shared by the instances, on the lists of the work list name
+	Redis string `json:"redis,omitempty"`
+	// DB is the key of the database of the manager where the works are shared by the instances, on the table (the work list name by default)
+	DB    string `json:"db,omitempty"`
+	Table string `json:"table,omitempty"`
+	// VisibilityTimeout is the time the works removed from redis or from the database can take until they are done
+	VisibilityTimeout time.Duration `json:"visibility_timeout,omitempty" default:"1m" validate:"min=1ms"`
+}
+
+// newWorkListStorage creates the list of the works of a work list, the works
+// on a database are discarded when they are locked more times than the retries allow
+func (manager *Manager) newWorkListStorage(name string, mode Mode, maxRetries int, storage *WorkListStorageConfig) (IList, error) {
+	if storage.Redis != "" && storage.DB != "" {
+		return nil, fmt.Errorf("the work list can't have both a redis and a database [ name: %s ]", name)
+	}
+
+	if storage.DB != "" {
+		manager.registryMux.RLock()
+		db, exists := manager.dbs[storage.DB]
+		manager.registryMux.RUnlock()
+
+		if !exists {
+			return nil, fmt.Errorf("database of the work list not found [ name: %s, db: %s ]", name, storage.DB)
+		}
+
+		table := storage.Table
+		if table == "" {
+			table = name
+		}
+
+		options := []SQLListOption{WithSQLMode(mode), WithMaxAttempts(maxRetries + 1)}
+		if storage.VisibilityTimeout > 0 {
+			options = append(options, WithLockTimeout(storage.VisibilityTimeout))
+		}
+
+		list, err := manager.NewSQLList(db, table, options...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid table of the work list, the table must be set when the name isn't a valid table [ name: %s ]: %w", name, err)
+		}
+
+		return list, nil
+	}
+
+	if storage.Redis != "" {
+		manager.registryMux.RLock()
+		redis, exists := manager.redis[storage.Redis]
+		manager.registryMux.RUnlock()
+
+		if !exists {
+			return nil, fmt.Errorf("redis of the work list not found [ name: %s, redis: %s ]", name, storage.Redis)
+		}
+
+		options := []RedisListOption{WithRedisMode(mode), WithRedisMaxAttempts(maxRetries + 1)}
+		if storage.VisibilityTimeout > 0 {
+			options = append(options, WithVisibilityTimeout(storage.VisibilityTimeout))
+		}
+
+		return manager.NewRedisList(redis, name, options...), nil
+	}
+
+	options := []QueueOption{WithMode(mode)}
+	if storage.Persistence != "" {
+		options = append(options, WithPersistence(storage.Persistence))
+	}
+
+	queue, err := manager.newQueue(options...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the queue of the work list [ name: %s ]: %w", name, err)
+	}
+
+	return queue, nil
+}
+
 // AddWorkList ...
-func (manager *Manager) AddWorkList(key string, worklist IWorkList) error {
+func (manager *Manager) AddWorkList(key string, worklist IWorkList, options ...ComponentOption) error {
+	manager.registryMux.Lock()
+	if err := manager.addComponent(key, options...); err != nil {
+		manager.registryMux.Unlock()
+		return err
+	}
 	manager.worklist[key] = worklist
+	manager.registryMux.Unlock()
 	manager.logger.Infof("work list %s added", key)
 
-	return nil
+	return manager.startAdded(key)
 }
 
 // RemoveWorkList ...
 func (manager *Manager) RemoveWorkList(key string) (IWorkList, error) {
-	list := manager.worklist[key]
+	if err := manager.stopRemoved(kindWorkList, key); err != nil {
+		return nil, err
+	}
 
+	manager.registryMux.Lock()
+	list := manager.worklist[key]
 	delete(manager.worklist, key)
+	manager.removeComponent(key)
+	manager.registryMux.Unlock()
+
 	manager.logger.Infof("work list %s removed", key)
 
 	return list, nil
@@ -74,7 +169,11 @@ func (manager *Manager) RemoveWorkList(key string) (IWorkList, error) {
 
 // GetWorkList ...
 func (manager *Manager) GetWorkList(key string) IWorkList {
-	if list, exists := manager.worklist[key]; exists {
+	manager.registryMux.RLock()
+	list, exists := manager.worklist[key]
+	manager.registryMux.RUnlock()
+
+	if exists {
 		return list
 	}
 	manager.logger.Infof("work list %s doesn't exist", key)
@@ -1,6 +1,10 @@
 package manager
 
-import "github.com/joaosoft/logger"
+import (
+	"time"
+
+	"github.com/joaosoft/logger"
+)
 
 // ManagerOption ...
 type ManagerOption func(manager *Manager)
@@ -34,9 +38,59 @@ func WithLogLevel(level logger.Level) ManagerOption {
 	}
 }
 
+// WithShutdownTimeout sets the maximum time that the manager waits for the components to stop
+func WithShutdownTimeout(timeout time.Duration) ManagerOption {
+	return func(manager *Manager) {
+		manager.shutdownTimeout = timeout
+	}
+}
+
+// WithHealthCheckTimeout sets the maximum time that each health check can take
+func WithHealthCheckTimeout(timeout time.Duration) ManagerOption {
+	return func(manager *Manager) {
+		manager.healthCheckTimeout = timeout
+	}
+}
+
+// WithAdminAddress starts a dedicated listener with the /healthz, /readyz and /status endpoints
+func WithAdminAddress(address string) ManagerOption {
+	return func(manager *Manager) {
+		manager.adminAddress = address
+	}
+}
+
+// WithAdminWeb mounts the /healthz, /readyz and /status endpoints on the web added with the key
+func WithAdminWeb(key string) ManagerOption {
+	return func(manager *Manager) {
+		manager.adminWeb = key
+	}
+}
+
 // WithQuitChannel ...
 func WithQuitChannel(quit chan int) ManagerOption {
 	return func(manager *Manager) {
 		manager.quit = quit
 	}
 }
+
+// WithHandlers sets the registry of the handlers used by the components built by name
+func WithHandlers(handlers *HandlerRegistry) ManagerOption {
+	return func(manager *Manager) {
+		manager.handlers = handlers
+	}
+}
+
+// WithConfigWatch reloads the application config file when it changes, applying the new log level
+// and the new number of workers of the work lists
+func WithConfigWatch() ManagerOption {
+	return func(manager *Manager) {
+		if manager.appConfig == nil {
+			manager.logger.Error("the application config isn't loaded, it can't be watched")
+			return
+		}
+
+		if err := manager.WatchConfig(manager.appConfig); err != nil {
+			manager.logger.Error(err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package manager
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redisSubscription is a subscription of a channel on a dedicated connection, that is closed when unsubscribed.
+// the pubsub client of the redis library can't close its connections, so the subscription uses its own
+type redisSubscription struct {
+	channel  string
+	conn     net.Conn
+	reader   *bufio.Reader
+	messages chan []byte
+	done     chan struct{}
+	once     sync.Once
+}
+
+// newRedisSubscription connects and subscribes the channel, the connection is closed when it fails
+func newRedisSubscription(config *RedisConfig, channel string) (*redisSubscription, error) {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(config.Host, strconv.Itoa(config.Port)), 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &redisSubscription{
+		channel:  channel,
+		conn:     conn,
+		reader:   bufio.NewReader(conn),
+		messages: make(chan []byte, 100),
+		done:     make(chan struct{}),
+	}
+
+	if err := subscription.subscribe(config.Password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go subscription.receive()
+
+	return subscription, nil
+}
+
+// subscribe authenticates and subscribes the channel, waiting for the confirmation
+func (subscription *redisSubscription) subscribe(password string) error {
+	subscription.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	defer subscription.conn.SetDeadline(time.Time{})
+
+	if password != "" {
+		if err := subscription.send("AUTH", password); err != nil {
+			return err
+		}
+
+		if _, err := readRedisReply(subscription.reader); err != nil {
+			return err
+		}
+	}
+
+	if err := subscription.send("SUBSCRIBE", subscription.channel); err != nil {
+		return err
+	}
+
+	reply, err := readRedisReply(subscription.reader)
+	if err != nil {
+		return err
+	}
+
+	if kind, _ := redisReplyKind(reply); kind != "subscribe" {
+		return fmt.Errorf("unexpected reply subscribing the redis channel [ channel: %s ]", subscription.channel)
+	}
+
+	return nil
+}
+
+// send writes the command encoded as an array of bulk strings
+func (subscription *redisSubscription) send(args ...string) error {
+	var builder strings.Builder
+	builder.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		builder.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+
+	_, err := io.WriteString(subscription.conn, builder.String())
+	return err
+}
+
+// receive reads the messages of the channel until the connection is closed
+func (subscription *redisSubscription) receive() {
+	defer close(subscription.messages)
+
+	for {
+		reply, err := readRedisReply(subscription.reader)
+		if err != nil {
+			return
+		}
+
+		kind, values := redisReplyKind(reply)
+		if kind != "message" || len(values) != 3 {
+			continue
+		}
+
+		message, _ := values[2].([]byte)
+		select {
+		case subscription.messages <- message:
+		case <-subscription.done:
+			return
+		}
+	}
+}
+
+// unsubscribe closes the connection, ending the subscription
+func (subscription *redisSubscription) unsubscribe() error {
+	var err error
+	subscription.once.Do(func() {
+		close(subscription.done)
+		err = subscription.conn.Close()
+	})
+
+	return err
+}
+
+// redisReplyKind gets the kind of a pubsub reply (ex: subscribe or message) and its values
+func redisReplyKind(reply interface{}) (string, []interface{}) {
+	values, ok := reply.([]interface{})
+	if !ok || len(values) == 0 {
+		return "", nil
+	}
+
+	kind, _ := values[0].([]byte)
+	return string(kind), values
+}
+
+// readRedisReply reads a reply of the redis protocol, the bulk strings are returned as bytes
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\r\n")
+	if len(line) == 0 {
+		return nil, errors.New("invalid redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis error: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		values := make([]interface{}, size)
+		for i := range values {
+			if values[i], err = readRedisReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("invalid redis reply [ type: %c ]", line[0])
+	}
+}
@@ -0,0 +1,319 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+// defaultPollInterval ...
+const defaultPollInterval = 30 * time.Second
+
+// IRemoteConfigSource loads the key/value pairs of a remote config, the keys have the levels separated by dots
+// (ex: manager.log.level) and the values that aren't valid json are used as text
+type IRemoteConfigSource interface {
+	Load(ctx context.Context) (map[string]string, error)
+}
+
+// IRemoteConfigStore can be implemented by the sources that can save the key/value pairs
+type IRemoteConfigStore interface {
+	Store(ctx context.Context, values map[string]string) error
+}
+
+// IRemoteConfigNotifier can be implemented by the sources that notify the changes,
+// the channel is closed when the context is done or the notifications end
+type IRemoteConfigNotifier interface {
+	Notify(ctx context.Context) (<-chan struct{}, error)
+}
+
+// RemoteConfig is a config loaded from a remote source, cached locally and reloaded by polling the source
+// or when the source notifies a change
+type RemoteConfig struct {
+	source       IRemoteConfigSource
+	pollInterval time.Duration
+	timeout      time.Duration
+	cacheFile    string
+	watch        bool
+	cancel       context.CancelFunc
+	logger       logger.ILogger
+	reloadMux    sync.Mutex
+	*configValues
+}
+
+// RemoteConfigOption ...
+type RemoteConfigOption func(remote *RemoteConfig)
+
+// Reconfigure ...
+func (remote *RemoteConfig) Reconfigure(options ...RemoteConfigOption) {
+	for _, option := range options {
+		option(remote)
+	}
+}
+
+// WithPollInterval sets the interval between the loads of the source when watching (positive), 30 seconds by default
+func WithPollInterval(interval time.Duration) RemoteConfigOption {
+	return func(remote *RemoteConfig) {
+		remote.pollInterval = interval
+	}
+}
+
+// WithRemoteTimeout sets the maximum time of each load of the source
+func WithRemoteTimeout(timeout time.Duration) RemoteConfigOption {
+	return func(remote *RemoteConfig) {
+		remote.timeout = timeout
+	}
+}
+
+// WithRemoteCacheFile keeps the last loaded configuration on the file,
+// that is used when the source can't be loaded on the creation of the config
+func WithRemoteCacheFile(file string) RemoteConfigOption {
+	return func(remote *RemoteConfig) {
+		remote.cacheFile = file
+	}
+}
+
+// WithRemoteWatch reloads the configuration by polling the source or when the source notifies a change
+func WithRemoteWatch() RemoteConfigOption {
+	return func(remote *RemoteConfig) {
+		remote.watch = true
+	}
+}
+
+// NewRemoteConfig loads the config from the source into the object
+func NewRemoteConfig(source IRemoteConfigSource, obj interface{}, options ...RemoteConfigOption) (*RemoteConfig, error) {
+	return newRemoteConfig(source, obj, logger.Instance, options...)
+}
+
+// NewRemoteConfig ...
+func (manager *Manager) NewRemoteConfig(source IRemoteConfigSource, obj interface{}, options ...RemoteConfigOption) (*RemoteConfig, error) {
+	return newRemoteConfig(source, obj, manager.logger, options...)
+}
+
+func newRemoteConfig(source IRemoteConfigSource, obj interface{}, logger logger.ILogger, options ...RemoteConfigOption) (*RemoteConfig, error) {
+	remote := &RemoteConfig{
+		source:       source,
+		pollInterval: defaultPollInterval,
+		timeout:      10 * time.Second,
+		logger:       logger,
+		configValues: newConfigValues(obj),
+	}
+	remote.Reconfigure(options...)
+
+	if remote.pollInterval <= 0 {
+		return nil, fmt.Errorf("the poll interval of the remote config must be positive [ interval: %s ]", remote.pollInterval)
+	}
+
+	if err := remote.Reload(); err != nil {
+		if remote.cacheFile == "" {
+			return nil, err
+		}
+
+		remote.logger.Errorf("error loading the remote config, using the cache file [ file: %s ]: %s", remote.cacheFile, err)
+		if err := remote.loadCache(); err != nil {
+			return nil, err
+		}
+	}
+
+	if remote.watch {
+		if err := remote.Watch(); err != nil {
+			return nil, err
+		}
+	}
+
+	return remote, nil
+}
+
+// Save stores the object on the source, as key/value pairs, and reloads the configuration
+func (remote *RemoteConfig) Save() error {
+	store, ok := remote.source.(IRemoteConfigStore)
+	if !ok {
+		return fmt.Errorf("the remote config source can't be saved [ source: %T ]", remote.source)
+	}
+
+	data, err := json.Marshal(remote.GetObj())
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	flat := make(map[string]interface{})
+	flattenConfigValues("", values, flat)
+
+	pairs := make(map[string]string, len(flat))
+	for key, value := range flat {
+		pairs[key] = encodeRemoteValue(value)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remote.timeout)
+	defer cancel()
+
+	if err := store.Store(ctx, pairs); err != nil {
+		return err
+	}
+
+	return remote.Reload()
+}
+
+// Reload loads again the configuration from the source, the new configuration is only used when it is valid
+func (remote *RemoteConfig) Reload() error {
+	remote.reloadMux.Lock()
+	defer remote.reloadMux.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), remote.timeout)
+	defer cancel()
+
+	pairs, err := remote.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	for key, value := range pairs {
+		setConfigValue(values, key, decodeRemoteValue(value))
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	if err := remote.use(data); err != nil {
+		return err
+	}
+
+	if remote.cacheFile != "" {
+		if err := os.WriteFile(remote.cacheFile, data, 0600); err != nil {
+			remote.logger.Errorf("error writing the remote config cache file [ file: %s ]: %s", remote.cacheFile, err)
+		}
+	}
+
+	return nil
+}
+
+// Watch reloads the configuration by polling the source and when the source notifies a change.
+// when the new configuration can't be loaded or is invalid, the error is logged and the current configuration is kept
+func (remote *RemoteConfig) Watch() error {
+	remote.mux.Lock()
+	defer remote.mux.Unlock()
+
+	if remote.cancel != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var notifications <-chan struct{}
+	if notifier, ok := remote.source.(IRemoteConfigNotifier); ok {
+		var err error
+		if notifications, err = notifier.Notify(ctx); err != nil {
+			cancel()
+			return err
+		}
+	}
+
+	remote.cancel = cancel
+	go remote.watchSource(ctx, notifications)
+
+	return nil
+}
+
+// StopWatch ...
+func (remote *RemoteConfig) StopWatch() error {
+	remote.mux.Lock()
+	cancel := remote.cancel
+	remote.cancel = nil
+	remote.mux.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	return nil
+}
+
+// watchSource ...
+func (remote *RemoteConfig) watchSource(ctx context.Context, notifications <-chan struct{}) {
+	ticker := time.NewTicker(remote.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case _, ok := <-notifications:
+			if !ok {
+				// the polling goes on without the notifications
+				notifications = nil
+				continue
+			}
+		}
+
+		if err := remote.Reload(); err != nil {
+			remote.logger.Errorf("error reloading the remote config, keeping the current configuration: %s", err)
+		}
+	}
+}
+
+// use decodes and validates the configuration, and notifies the changes
+func (remote *RemoteConfig) use(data []byte) error {
+	obj, err := remote.decode(data)
+	if err != nil {
+		return err
+	}
+
+	if diff, subscribers := remote.apply(data, obj, nil); diff != nil {
+		for _, subscriber := range subscribers {
+			subscriber(diff)
+		}
+	}
+
+	return nil
+}
+
+// loadCache ...
+func (remote *RemoteConfig) loadCache() error {
+	data, err := os.ReadFile(remote.cacheFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("the remote config can't be loaded and there's no cache file [ file: %s ]", remote.cacheFile)
+		}
+		return err
+	}
+
+	return remote.use(data)
+}
+
+// decodeRemoteValue decodes the json values, the other ones are used as text
+func decodeRemoteValue(value string) interface{} {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(value)), &decoded); err == nil {
+		return decoded
+	}
+
+	return value
+}
+
+// encodeRemoteValue encodes the values as json, except the text that isn't read as another type
+func encodeRemoteValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		if decoded, isText := decodeRemoteValue(text).(string); isText && decoded == text {
+			return text
+		}
+	}
+
+	data, _ := json.Marshal(value)
+	return string(data)
+}
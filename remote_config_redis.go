@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+)
+
+// RedisConfigSource loads the config from a redis hash, with the keys as fields.
+// when the channel is given, the saves are published on it and, with a redis that implements IRedisSubscriber,
+// the changes are notified by the messages published on it
+type RedisConfigSource struct {
+	redis   IRedis
+	hash    string
+	channel string
+}
+
+// NewRedisConfigSource ...
+func NewRedisConfigSource(redis IRedis, hash, channel string) *RedisConfigSource {
+	return &RedisConfigSource{
+		redis:   redis,
+		hash:    hash,
+		channel: channel,
+	}
+}
+
+// Load ...
+func (source *RedisConfigSource) Load(ctx context.Context) (map[string]string, error) {
+	fields, err := source.redis.Hgetall(source.hash)
+	if err != nil {
+		return nil, fmt.Errorf("error loading the redis config [ hash: %s ]: %w", source.hash, err)
+	}
+
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("invalid redis config hash [ hash: %s, fields: %d ]", source.hash, len(fields))
+	}
+
+	values := make(map[string]string, len(fields)/2)
+	for i := 0; i < len(fields); i += 2 {
+		values[string(fields[i])] = string(fields[i+1])
+	}
+
+	return values, nil
+}
+
+// Store sets the fields of the hash and publishes the change on the channel
+func (source *RedisConfigSource) Store(ctx context.Context, values map[string]string) error {
+	for key, value := range values {
+		if err := source.redis.Hset(source.hash, key, []byte(value)); err != nil {
+			return fmt.Errorf("error storing the redis config [ hash: %s, key: %s ]: %w", source.hash, key, err)
+		}
+	}
+
+	if source.channel != "" {
+		if _, err := source.redis.Publish(source.channel, []byte(source.hash)); err != nil {
+			return fmt.Errorf("error publishing the redis config change [ channel: %s ]: %w", source.channel, err)
+		}
+	}
+
+	return nil
+}
+
+// Notify subscribes the channel, when there's no channel or the redis can't subscribe the config is only polled
+func (source *RedisConfigSource) Notify(ctx context.Context) (<-chan struct{}, error) {
+	subscriber, ok := source.redis.(IRedisSubscriber)
+	if source.channel == "" || !ok {
+		return nil, nil
+	}
+
+	messages, unsubscribe, err := subscriber.Subscribe(source.channel)
+	if err != nil {
+		return nil, fmt.Errorf("error subscribing the redis config changes [ channel: %s ]: %w", source.channel, err)
+	}
+
+	notifications := make(chan struct{}, 1)
+	go func() {
+		defer close(notifications)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				// the notifications are merged while a reload is pending
+				select {
+				case notifications <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return notifications, nil
+}
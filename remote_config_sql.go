@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// sqlIdentifier matches the valid table names, with an optional schema
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLConfigSource loads the config from a table with the columns name and value, the changes are polled
+type SQLConfigSource struct {
+	db    IDB
+	table string
+}
+
+// NewSQLConfigSource ...
+func NewSQLConfigSource(db IDB, table string) *SQLConfigSource {
+	return &SQLConfigSource{
+		db:    db,
+		table: table,
+	}
+}
+
+// Migrate creates the table of the config when it doesn't exist
+func (source *SQLConfigSource) Migrate(ctx context.Context) error {
+	if err := validateSQLIdentifier(source.table); err != nil {
+		return err
+	}
+
+	_, err := source.db.Get().ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (name VARCHAR(255) NOT NULL PRIMARY KEY, value TEXT NOT NULL)", source.table))
+
+	return err
+}
+
+// Load ...
+func (source *SQLConfigSource) Load(ctx context.Context) (map[string]string, error) {
+	if err := validateSQLIdentifier(source.table); err != nil {
+		return nil, err
+	}
+
+	rows, err := source.db.Get().QueryContext(ctx, fmt.Sprintf("SELECT name, value FROM %s", source.table))
+	if err != nil {
+		return nil, fmt.Errorf("error loading the sql config [ table: %s ]: %w", source.table, err)
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+
+	return values, rows.Err()
+}
+
+// Store inserts or updates the values, in a transaction
+func (source *SQLConfigSource) Store(ctx context.Context, values map[string]string) (err error) {
+	if err := validateSQLIdentifier(source.table); err != nil {
+		return err
+	}
+
+	db := source.db.Get()
+
+	var upsert string
+	switch sqlDialect(db) {
+	case sqlDialectPostgres:
+		upsert = fmt.Sprintf("INSERT INTO %s (value, name) VALUES ($1, $2) ON CONFLICT (name) DO UPDATE SET value = EXCLUDED.value", source.table)
+	case sqlDialectMySQL:
+		upsert = fmt.Sprintf("INSERT INTO %s (value, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE value = VALUES(value)", source.table)
+	}
+	update := fmt.Sprintf("UPDATE %s SET value = ? WHERE name = ?", source.table)
+	insert := fmt.Sprintf("INSERT INTO %s (value, name) VALUES (?, ?)", source.table)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for name, value := range values {
+		if upsert != "" {
+			if _, err := tx.ExecContext(ctx, upsert, value, name); err != nil {
+				return fmt.Errorf("error storing the sql config [ table: %s, name: %s ]: %w", source.table, name, err)
+			}
+			continue
+		}
+
+		result, err := tx.ExecContext(ctx, update, value, name)
+		if err != nil {
+			return fmt.Errorf("error storing the sql config [ table: %s, name: %s ]: %w", source.table, name, err)
+		}
+
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, insert, value, name); err != nil {
+			return fmt.Errorf("error storing the sql config [ table: %s, name: %s ]: %w", source.table, name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// validateSQLIdentifier ...
+func validateSQLIdentifier(name string) error {
+	if !sqlIdentifier.MatchString(name) {
+		return fmt.Errorf("invalid sql table name [ name: %s ]", name)
+	}
+
+	return nil
+}
+
+const (
+	sqlDialectPostgres = "postgres"
+	sqlDialectMySQL    = "mysql"
+)
+
+// sqlDialect gets the dialect of the database by its driver, postgres, mysql or empty for the other ones
+func sqlDialect(db *sql.DB) string {
+	driver := reflect.TypeOf(db.Driver()).String()
+
+	switch {
+	case strings.HasPrefix(driver, "*pq."):
+		return sqlDialectPostgres
+	case strings.HasPrefix(driver, "*mysql."), strings.HasPrefix(driver, "mysql."):
+		return sqlDialectMySQL
+	default:
+		return ""
+	}
+}
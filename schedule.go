@@ -0,0 +1,63 @@
+package manager
+
+import "time"
+
+// ISchedule ...
+type ISchedule interface {
+	// Next returns the next activation time after the given time
+	Next(t time.Time) time.Time
+}
+
+// IScheduled can be implemented by the components that run on a schedule,
+// the next run is exposed through the manager status
+type IScheduled interface {
+	NextRun() time.Time
+}
+
+// OverlapPolicy defines what happens when a run is due while the previous one is still running
+type OverlapPolicy string
+
+const (
+	// OverlapSkip skips the run
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue runs it after the running one ends
+	OverlapQueue OverlapPolicy = "queue"
+	// OverlapAllow runs it concurrently
+	OverlapAllow OverlapPolicy = "allow"
+)
+
+const defaultScheduleHistorySize = 10
+
+// ScheduledRun ...
+type ScheduledRun struct {
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	StartedAt   time.Time     `json:"started_at,omitempty"`
+	FinishedAt  time.Time     `json:"finished_at,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	Skipped     bool          `json:"skipped,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// ScheduleOption ...
+type ScheduleOption func(process *ScheduledProcess)
+
+// WithOverlapPolicy ...
+func WithOverlapPolicy(policy OverlapPolicy) ScheduleOption {
+	return func(process *ScheduledProcess) {
+		process.overlap = policy
+	}
+}
+
+// WithTimezone sets the location where the schedule is evaluated
+func WithTimezone(location *time.Location) ScheduleOption {
+	return func(process *ScheduledProcess) {
+		process.location = location
+	}
+}
+
+// WithHistorySize sets how many runs are kept in the history
+func WithHistorySize(size int) ScheduleOption {
+	return func(process *ScheduledProcess) {
+		process.historySize = size
+	}
+}
@@ -0,0 +1,193 @@
+package manager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule activates on the times matched by a standard five fields cron expression
+// (minute, hour, day of month, month and day of week)
+type CronSchedule struct {
+	expression string
+	minutes    uint64
+	hours      uint64
+	days       uint64
+	months     uint64
+	weekdays   uint64
+	anyDay     bool
+	anyWeekday bool
+}
+
+// cronField ...
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinute   = cronField{name: "minute", min: 0, max: 59}
+	cronHour     = cronField{name: "hour", min: 0, max: 23}
+	cronDay      = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth    = cronField{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	cronWeekday  = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+	cronMacros   = map[string]string{"@yearly": "0 0 1 1 *", "@annually": "0 0 1 1 *", "@monthly": "0 0 1 * *", "@weekly": "0 0 * * 0", "@daily": "0 0 * * *", "@midnight": "0 0 * * *", "@hourly": "0 * * * *"}
+	cronMaxYears = 5
+)
+
+// ParseCron parses a five fields cron expression, the macros @yearly, @monthly, @weekly, @daily and @hourly are also supported
+func ParseCron(expression string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expression)
+	if macro, exists := cronMacros[strings.ToLower(spec)]; exists {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression [ expression: %s ]: expected 5 fields, got %d", expression, len(fields))
+	}
+
+	schedule := &CronSchedule{
+		expression: expression,
+		anyDay:     fields[2] == "*" || fields[2] == "?",
+		anyWeekday: fields[4] == "*" || fields[4] == "?",
+	}
+
+	var err error
+	for i, parse := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{cronMinute, &schedule.minutes},
+		{cronHour, &schedule.hours},
+		{cronDay, &schedule.days},
+		{cronMonth, &schedule.months},
+		{cronWeekday, &schedule.weekdays},
+	} {
+		if *parse.bits, err = parse.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("invalid cron expression [ expression: %s ]: %w", expression, err)
+		}
+	}
+
+	// sunday can be both 0 and 7
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+
+	return schedule, nil
+}
+
+// String ...
+func (schedule *CronSchedule) String() string {
+	return schedule.expression
+}
+
+// Next ...
+func (schedule *CronSchedule) Next(t time.Time) time.Time {
+	location := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, location).Add(time.Minute)
+	limit := t.AddDate(cronMaxYears, 0, 0)
+
+	for t.Before(limit) {
+		if schedule.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if !schedule.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if schedule.hours&(1<<uint(t.Hour())) == 0 {
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			continue
+		}
+
+		if schedule.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay when both the day of month and the day of week are restricted, any of them matches
+func (schedule *CronSchedule) matchDay(t time.Time) bool {
+	day := schedule.days&(1<<uint(t.Day())) != 0
+	weekday := schedule.weekdays&(1<<uint(t.Weekday())) != 0
+
+	if schedule.anyDay || schedule.anyWeekday {
+		return day && weekday
+	}
+
+	return day || weekday
+}
+
+// parse parses a field with lists, ranges and steps into a bit set
+func (field cronField) parse(value string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(value, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step on %s [ value: %s ]", field.name, part)
+			}
+		}
+
+		start, end := field.min, field.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = field.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if end, err = field.value(bounds[1]); err != nil {
+				return 0, err
+			}
+		default:
+			var err error
+			if start, err = field.value(rangePart); err != nil {
+				return 0, err
+			}
+			if !strings.Contains(part, "/") {
+				end = start
+			}
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("invalid range on %s [ value: %s ]", field.name, part)
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+// value ...
+func (field cronField) value(value string) (int, error) {
+	if n, exists := field.names[strings.ToLower(value)]; exists {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < field.min || n > field.max {
+		return 0, fmt.Errorf("invalid %s [ value: %s ]", field.name, value)
+	}
+
+	return n, nil
+}
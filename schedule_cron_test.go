@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		expected   time.Time
+	}{
+		{"every minute", "* * * * *", date(2024, 1, 15, 10, 30, 0), date(2024, 1, 15, 10, 31, 0)},
+		{"every hour", "0 * * * *", date(2024, 1, 15, 10, 30, 0), date(2024, 1, 15, 11, 0, 0)},
+		{"after the time matched", "30 10 * * *", date(2024, 1, 15, 10, 30, 0), date(2024, 1, 16, 10, 30, 0)},
+		{"step", "*/15 * * * *", date(2024, 1, 15, 10, 30, 45), date(2024, 1, 15, 10, 45, 0)},
+		{"list", "5,20 * * * *", date(2024, 1, 15, 10, 10, 0), date(2024, 1, 15, 10, 20, 0)},
+		{"first day of month", "0 0 1 * *", date(2024, 1, 15, 10, 30, 0), date(2024, 2, 1, 0, 0, 0)},
+		{"macro", "@yearly", date(2024, 1, 15, 10, 30, 0), date(2025, 1, 1, 0, 0, 0)},
+		{"week days by name", "0 9 * * mon-fri", date(2024, 1, 19, 10, 0, 0), date(2024, 1, 22, 9, 0, 0)},
+		{"sunday as 7", "0 0 * * 7", date(2024, 1, 15, 10, 30, 0), date(2024, 1, 21, 0, 0, 0)},
+		{"day of month or day of week", "0 12 13 * 5", date(2024, 1, 15, 10, 30, 0), date(2024, 1, 19, 12, 0, 0)},
+		{"leap day", "0 0 29 2 *", date(2024, 3, 1, 0, 0, 0), date(2028, 2, 29, 0, 0, 0)},
+		{"never", "0 0 31 2 *", date(2024, 1, 15, 10, 30, 0), time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := ParseCron(test.expression)
+			if err != nil {
+				t.Fatalf("error parsing the expression %q: %s", test.expression, err)
+			}
+
+			if next := schedule.Next(test.from); !next.Equal(test.expected) {
+				t.Errorf("next of %q after %s is %s, expected %s", test.expression, test.from, next, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"missing field", "* * * *"},
+		{"out of range", "60 * * * *"},
+		{"invalid step", "*/0 * * * *"},
+		{"inverted range", "5-1 * * * *"},
+		{"unknown name", "* * * foo *"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseCron(test.expression); err == nil {
+				t.Errorf("expected an error parsing %q", test.expression)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package manager
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// IntervalSchedule activates on every interval plus a random jitter
+type IntervalSchedule struct {
+	Interval time.Duration
+	Jitter   time.Duration
+}
+
+// NewIntervalSchedule creates the schedule, the interval must be positive
+func NewIntervalSchedule(interval, jitter time.Duration) (*IntervalSchedule, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("the interval of the schedule must be positive [ interval: %s ]", interval)
+	}
+
+	return &IntervalSchedule{
+		Interval: interval,
+		Jitter:   jitter,
+	}, nil
+}
+
+// Next ...
+func (schedule *IntervalSchedule) Next(t time.Time) time.Time {
+	next := t.Add(schedule.Interval)
+	if schedule.Jitter > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(schedule.Jitter))))
+	}
+
+	return next
+}
@@ -0,0 +1,42 @@
+package manager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalScheduleNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		jitter   time.Duration
+		valid    bool
+	}{
+		{"interval", time.Minute, 0, true},
+		{"interval with jitter", time.Minute, time.Second, true},
+		{"zero interval", 0, 0, false},
+		{"negative interval", -time.Second, 0, false},
+	}
+
+	from := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := NewIntervalSchedule(test.interval, test.jitter)
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected an error with the interval %s", test.interval)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error creating the schedule: %s", err)
+			}
+
+			next := schedule.Next(from)
+			if next.Before(from.Add(test.interval)) || !next.Before(from.Add(test.interval+test.jitter+1)) {
+				t.Errorf("next after %s is %s, expected between the interval %s and the jitter %s", from, next, test.interval, test.jitter)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/joaosoft/logger"
@@ -16,38 +18,48 @@ type SimpleBulkWorkList struct {
 	list                                IList
 	workers                             []*BulkWorker
 	logger                              logger.ILogger
-	started                             bool
+	mux                                 sync.Mutex
+	state                               *StateMachine
 }
 
-// NewSimpleBulkWorkList ...
-func (manager *Manager) NewSimpleBulkWorkList(config *BulkWorkListConfig, handler BulkWorkHandler, bulkWorkRecoverHandler BulkWorkRecoverHandler, bulkWorkRecoverWastedRetriesHandler BulkWorkRecoverWastedRetriesHandler) IWorkList {
+// NewSimpleBulkWorkList creates the work list with the list of the storage of the config, it returns an error when
+// the list can't be created (ex: the log can't be opened, or the redis or the database of the config wasn't added)
+func (manager *Manager) NewSimpleBulkWorkList(config *BulkWorkListConfig, handler BulkWorkHandler, bulkWorkRecoverHandler BulkWorkRecoverHandler, bulkWorkRecoverWastedRetriesHandler BulkWorkRecoverWastedRetriesHandler) (IWorkList, error) {
+	list, err := manager.newWorkListStorage(config.Name, config.Mode, config.MaxRetries, &config.WorkListStorageConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.newSimpleBulkWorkList(config, list, handler, bulkWorkRecoverHandler, bulkWorkRecoverWastedRetriesHandler), nil
+}
+
+// newSimpleBulkWorkList ...
+func (manager *Manager) newSimpleBulkWorkList(config *BulkWorkListConfig, list IList, handler BulkWorkHandler, bulkWorkRecoverHandler BulkWorkRecoverHandler, bulkWorkRecoverWastedRetriesHandler BulkWorkRecoverWastedRetriesHandler) IWorkList {
 	return &SimpleBulkWorkList{
 		name:                                config.Name,
-		list:                                manager.NewQueue(WithMode(config.Mode)),
+		list:                                list,
 		config:                              config,
 		handler:                             handler,
 		bulkWorkRecoverHandler:              bulkWorkRecoverHandler,
 		bulkWorkRecoverWastedRetriesHandler: bulkWorkRecoverWastedRetriesHandler,
 		logger:                              manager.logger,
+		state:                               NewStateMachine(),
 	}
 }
 
 // Start ...
-func (bulkWorklist *SimpleBulkWorkList) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (bulkWorklist *SimpleBulkWorkList) Start(ctx context.Context) (err error) {
+	bulkWorklist.mux.Lock()
+	defer bulkWorklist.mux.Unlock()
 
-	if bulkWorklist.started {
+	if !bulkWorklist.state.start() {
 		return nil
 	}
+	defer func() { bulkWorklist.state.started(err) }()
+
+	if err := openList(bulkWorklist.list); err != nil {
+		return err
+	}
 
 	var workers []*BulkWorker
 	for i := 1; i <= bulkWorklist.config.MaxWorkers; i++ {
@@ -58,46 +70,92 @@ func (bulkWorklist *SimpleBulkWorkList) Start(waitGroup ...*sync.WaitGroup) erro
 	}
 	bulkWorklist.workers = workers
 
-	bulkWorklist.started = true
-
 	return nil
 }
 
-// Stop ...
-func (bulkWorklist *SimpleBulkWorkList) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+// Stop stops the workers, waiting for the works being done without holding the lock
+func (bulkWorklist *SimpleBulkWorkList) Stop(ctx context.Context) (err error) {
+	bulkWorklist.mux.Lock()
+	if !bulkWorklist.state.stop() {
+		bulkWorklist.mux.Unlock()
+		return nil
+	}
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+	workers := bulkWorklist.workers
+	bulkWorklist.workers = nil
+	bulkWorklist.mux.Unlock()
+	defer func() { bulkWorklist.state.stopped(err) }()
+
+	stopped := make(chan bool)
+	go func() {
+		for _, worker := range workers {
+			bulkWorklist.logger.Infof("stopping worker [ %d: %s ]", worker.id, worker.name)
+			worker.Stop()
+		}
+
+		// the list is closed when the workers are stopped, even after the timeout of the stop
+		closeList(bulkWorklist.list)
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 
-	defer wg.Done()
+	return nil
+}
+
+// Started ...
+func (bulkWorklist *SimpleBulkWorkList) Started() bool {
+	return bulkWorklist.state.Started()
+}
+
+// SetMaxWorkers changes the number of workers, starting or stopping workers when the work list is started
+func (bulkWorklist *SimpleBulkWorkList) SetMaxWorkers(maxWorkers int) error {
+	if maxWorkers < 1 {
+		return fmt.Errorf("invalid max workers [ name: %s, max workers: %d ]", bulkWorklist.name, maxWorkers)
+	}
 
-	if !bulkWorklist.started {
+	bulkWorklist.mux.Lock()
+	bulkWorklist.config.MaxWorkers = maxWorkers
+	if !bulkWorklist.state.Started() {
+		bulkWorklist.mux.Unlock()
 		return nil
 	}
 
-	for _, worker := range bulkWorklist.workers {
+	for len(bulkWorklist.workers) < maxWorkers {
+		worker := NewBulkWorker(len(bulkWorklist.workers)+1, bulkWorklist.config, bulkWorklist.handler, bulkWorklist.list, bulkWorklist.bulkWorkRecoverHandler, bulkWorklist.bulkWorkRecoverWastedRetriesHandler, bulkWorklist.logger)
+		bulkWorklist.logger.Infof("starting worker [ %d ]", worker.id)
+		worker.Start()
+		bulkWorklist.workers = append(bulkWorklist.workers, worker)
+	}
+
+	// the workers removed are stopped without holding the lock, while they finish their works
+	var stopping []*BulkWorker
+	for len(bulkWorklist.workers) > maxWorkers {
+		stopping = append(stopping, bulkWorklist.workers[len(bulkWorklist.workers)-1])
+		bulkWorklist.workers = bulkWorklist.workers[:len(bulkWorklist.workers)-1]
+	}
+	bulkWorklist.mux.Unlock()
+
+	for _, worker := range stopping {
 		bulkWorklist.logger.Infof("stopping worker [ %d: %s ]", worker.id, worker.name)
 		worker.Stop()
 	}
 
-	bulkWorklist.started = false
-
 	return nil
 }
 
-// Started ...
-func (bulkWorklist *SimpleBulkWorkList) Started() bool {
-	return bulkWorklist.started
-}
-
-// AddWork ...
-func (bulkWorklist *SimpleBulkWorkList) AddWork(id string, data interface{}) {
+// AddWork adds the work to the list, returning the error when it can't be added (ex: the list was closed by a stop)
+func (bulkWorklist *SimpleBulkWorkList) AddWork(id string, data interface{}) error {
 	bulkWorklist.logger.Infof("adding work to the list [ name: %s ]", bulkWorklist.name)
 	work := NewWork(id, data, bulkWorklist.logger)
-	bulkWorklist.list.Add(id, work)
+	if err := bulkWorklist.list.Add(id, work); err != nil {
+		bulkWorklist.logger.Errorf("error adding work to the list [ name: %s, id: %s ]: %s", bulkWorklist.name, id, err)
+		return err
+	}
+
+	return nil
 }
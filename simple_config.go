@@ -1,144 +1,139 @@
 package manager
 
 import (
-	"time"
-
-	"github.com/joaosoft/logger"
-
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
 
+	"github.com/fsnotify/fsnotify"
+	"github.com/joaosoft/logger"
 	"github.com/spf13/viper"
 )
 
 // SimpleConfig ...
 type SimpleConfig struct {
-	file   string
-	obj    interface{}
-	bytes  []byte
-	viper  *viper.Viper
-	logger logger.ILogger
-}
-
-func NewSimpleConfig(file string, obj interface{}) (IConfig, error) {
-	if bytes, err := ReadFile(file, obj); err != nil {
+	file       string
+	baseFiles  []string
+	envPrefix  string
+	overrides  map[string]interface{}
+	resolvers  map[string]ISecretResolver
+	history    IConfigHistoryStore
+	secrets    []*configSecret
+	watch      bool
+	watcher    *fsnotify.Watcher
+	validators []func(obj interface{}) error
+	logger     logger.ILogger
+	saveMux    sync.Mutex
+	*configValues
+}
+
+// NewSimpleConfig loads the config file into the object, merged with the layers of the options
+func NewSimpleConfig(file string, obj interface{}, options ...ConfigOption) (IConfig, error) {
+	simple, err := newSimpleConfig(file, obj, logger.Instance, options...)
+	if err != nil {
 		return nil, err
-	} else {
-		return &SimpleConfig{
-			file:   file,
-			obj:    obj,
-			bytes:  bytes,
-			viper:  loadViper(bytes),
-			logger: logger.Instance,
-		}, err
 	}
+
+	return simple, nil
 }
 
 // NewSimpleConfig...
-func (manager *Manager) NewSimpleConfig(file string, obj interface{}) (IConfig, error) {
-	if bytes, err := ReadFile(file, obj); err != nil {
+func (manager *Manager) NewSimpleConfig(file string, obj interface{}, options ...ConfigOption) (IConfig, error) {
+	simple, err := newSimpleConfig(file, obj, manager.logger, options...)
+	if err != nil {
 		return nil, err
-	} else {
-		return &SimpleConfig{
-			file:   file,
-			obj:    obj,
-			bytes:  bytes,
-			viper:  loadViper(bytes),
-			logger: manager.logger,
-		}, err
 	}
-}
-
-// Get ...
-func (simple *SimpleConfig) Get(key string) interface{} {
-	return simple.viper.Get(key)
-}
 
-// GetString ...
-func (simple *SimpleConfig) GetString(key string) string {
-	return simple.viper.GetString(key)
+	return simple, nil
 }
 
-// GetBool ...
-func (simple *SimpleConfig) GetBool(key string) bool {
-	return simple.viper.GetBool(key)
-}
+func newSimpleConfig(file string, obj interface{}, logger logger.ILogger, options ...ConfigOption) (*SimpleConfig, error) {
+	simple := &SimpleConfig{
+		file:         file,
+		logger:       logger,
+		configValues: newConfigValues(obj),
+	}
+	simple.Reconfigure(options...)
 
-// GetInt ...
-func (simple *SimpleConfig) GetInt(key string) int {
-	return simple.viper.GetInt(key)
-}
+	if err := simple.Reload(); err != nil {
+		return nil, err
+	}
 
-// GetInt64 ...
-func (simple *SimpleConfig) GetInt64(key string) int64 {
-	return simple.viper.GetInt64(key)
-}
+	if simple.watch {
+		if err := simple.Watch(); err != nil {
+			return nil, err
+		}
+	}
 
-// GetFloat64 ...
-func (simple *SimpleConfig) GetFloat64(key string) float64 {
-	return simple.viper.GetFloat64(key)
+	return simple, nil
 }
 
-// GetTime ...
-func (simple *SimpleConfig) GetTime(key string) time.Time {
-	return simple.viper.GetTime(key)
-}
+// Reload loads again all the layers of the configuration, the new configuration is only used when it is valid
+func (simple *SimpleConfig) Reload() error {
+	data, secrets, err := simple.load()
+	if err != nil {
+		return err
+	}
 
-// GetDuration ...
-func (simple *SimpleConfig) GetDuration(key string) time.Duration {
-	return simple.viper.GetDuration(key)
-}
+	obj, err := simple.decode(data, simple.validators...)
+	if err != nil {
+		return err
+	}
 
-// GetStringSlice ...
-func (simple *SimpleConfig) GetStringSlice(key string) []string {
-	return simple.viper.GetStringSlice(key)
-}
+	// the secrets are changed with the configuration, so that the dumps always redact the resolved ones
+	var previousSecrets []*configSecret
+	diff, subscribers := simple.apply(data, obj, func() {
+		previousSecrets = simple.secrets
+		simple.secrets = secrets
+	})
+
+	if diff != nil {
+		diff.redact(append(previousSecrets, secrets...))
+		for _, subscriber := range subscribers {
+			subscriber(diff)
+		}
+	}
 
-// GetStringMap ...
-func (simple *SimpleConfig) GetStringMap(key string) map[string]interface{} {
-	return simple.viper.GetStringMap(key)
+	return nil
 }
 
-// GetStringMapString ...
-func (simple *SimpleConfig) GetStringMapString(key string) map[string]string {
-	return simple.viper.GetStringMapString(key)
-}
+// Dump returns the configuration encoded as json, with the resolved secrets redacted
+func (simple *SimpleConfig) Dump() ([]byte, error) {
+	simple.mux.RLock()
+	data := simple.bytes
+	secrets := simple.secrets
+	simple.mux.RUnlock()
 
-// GetStringMapStringSlice ...
-func (simple *SimpleConfig) GetStringMapStringSlice(key string) map[string][]string {
-	return simple.viper.GetStringMapStringSlice(key)
-}
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
 
-// GetObj ...
-func (simple *SimpleConfig) GetObj() interface{} {
-	return simple.obj
-}
+	for _, secret := range secrets {
+		replaceConfigValue(values, secret.path, secret.resolved, redacted)
+	}
 
-// Set ...
-func (simple *SimpleConfig) Set(config interface{}) {
-	simple.obj = simple
+	return json.MarshalIndent(values, "", "    ")
 }
 
-// Update ...
-func (simple *SimpleConfig) Reload() error {
-	if bytes, err := ReadFile(simple.file, simple.obj); err != nil {
-		return err
-	} else {
-		simple.viper = loadViper(simple.bytes)
-		simple.bytes = bytes
+// String returns the configuration with the resolved secrets redacted, so that it can be logged
+func (simple *SimpleConfig) String() string {
+	data, err := simple.Dump()
+	if err != nil {
+		return fmt.Sprintf("invalid config [ file: %s ]: %s", simple.file, err)
 	}
 
-	return nil
+	return string(data)
 }
 
-// Save ...
+// Save writes the object to the config file, without the values of the other layers and with the secret references
+// instead of the resolved secrets, the version is recorded on the history with the user of the process as author
 func (simple *SimpleConfig) Save() error {
-	if err := WriteFile(simple.file, simple.obj); err != nil {
-		return err
-	}
-
-	return simple.Reload()
+	return simple.SaveAs(configAuthor())
 }
 
+// loadViper loads the merged configuration, that is always encoded as json whatever the format of the files
 func loadViper(b []byte) *viper.Viper {
 	viper := viper.New()
 	viper.SetConfigType("json")
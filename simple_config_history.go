@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"time"
+)
+
+// SaveAs writes the object to the config file, without the values of the other layers and with the secret references
+// instead of the resolved secrets, and records the version on the history with the author.
+// when the saved configuration is invalid, the previous file is restored
+func (simple *SimpleConfig) SaveAs(author string) error {
+	simple.saveMux.Lock()
+	defer simple.saveMux.Unlock()
+
+	content, err := simple.content()
+	if err != nil {
+		return err
+	}
+
+	return simple.write(content, author, "")
+}
+
+// Versions returns the versions of the config file recorded on the history
+func (simple *SimpleConfig) Versions() ([]*ConfigVersion, error) {
+	if simple.history == nil {
+		return nil, fmt.Errorf("the config history isn't enabled [ file: %s ]", simple.file)
+	}
+
+	return simple.history.List()
+}
+
+// Rollback writes the content of the version to the config file and reloads it,
+// the rollback is recorded as a new version on the history
+func (simple *SimpleConfig) Rollback(id int) error {
+	if simple.history == nil {
+		return fmt.Errorf("the config history isn't enabled [ file: %s ]", simple.file)
+	}
+
+	simple.saveMux.Lock()
+	defer simple.saveMux.Unlock()
+
+	version, err := simple.history.Get(id)
+	if err != nil {
+		return err
+	}
+
+	content := make(map[string]interface{})
+	if err := json.Unmarshal(version.Content, &content); err != nil {
+		return fmt.Errorf("invalid config version [ file: %s, id: %d ]: %w", simple.file, id, err)
+	}
+
+	return simple.write(content, configAuthor(), fmt.Sprintf("rollback to version %d", id))
+}
+
+// content gets the config file layer to save, with the secret references instead of the resolved secrets
+func (simple *SimpleConfig) content() (interface{}, error) {
+	simple.mux.RLock()
+	obj := simple.obj
+	secrets := simple.secrets
+	simple.mux.RUnlock()
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	for _, secret := range secrets {
+		replaceConfigValue(values, secret.path, secret.resolved, secret.raw)
+	}
+
+	return simple.fileLayer(values)
+}
+
+// write writes the content to the config file and reloads it, recording the version on the history.
+// when the reload fails the previous file is restored
+func (simple *SimpleConfig) write(content interface{}, author, message string) error {
+	file := resolveFile(simple.file)
+
+	previous, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	existed := err == nil
+
+	if err := WriteFile(simple.file, content); err != nil {
+		return err
+	}
+
+	if err := simple.Reload(); err != nil {
+		if existed {
+			os.WriteFile(file, previous, 0644)
+		} else {
+			os.Remove(file)
+		}
+		return err
+	}
+
+	if simple.history == nil {
+		return nil
+	}
+
+	previousValues, err := decodeConfigValues(GetConfigFormat(simple.file), previous)
+	if err != nil {
+		previousValues = make(map[string]interface{})
+	}
+	previousData, _ := json.Marshal(previousValues)
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	return simple.history.Append(&ConfigVersion{
+		Timestamp: time.Now(),
+		Author:    author,
+		Message:   message,
+		Diff:      newConfigDiff(previousData, data),
+		Content:   data,
+	})
+}
+
+// configAuthor gets the user of the process, the author of the versions saved without one
+func configAuthor() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,345 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// load merges the layers of the configuration (base files, config file, environment variables and overrides),
+// and resolves the secret references. it returns the result encoded as json and the values with secrets
+func (simple *SimpleConfig) load() ([]byte, []*configSecret, error) {
+	values := make(map[string]interface{})
+
+	for _, file := range simple.baseFiles {
+		layer, err := readConfigValues(file)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, nil, err
+		}
+		mergeConfigValues(values, layer)
+	}
+
+	layer, err := readConfigValues(simple.file)
+	if err != nil {
+		return nil, nil, err
+	}
+	mergeConfigValues(values, layer)
+
+	if simple.envPrefix != "" {
+		simple.applyEnv(values)
+	}
+
+	for key, value := range simple.overrides {
+		setConfigValue(values, key, value)
+	}
+
+	secrets := make([]*configSecret, 0)
+	if _, err := simple.resolveSecrets(nil, values, &secrets); err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, secrets, nil
+}
+
+// fileLayer gets the values of the config file layer from the values of the object. the values of the lower layers
+// (defaults and base files) are only kept when changed or already on the file, and the keys of the higher layers
+// (environment variables and overrides) keep the values of the file, so that they aren't persisted on it
+func (simple *SimpleConfig) fileLayer(values map[string]interface{}) (map[string]interface{}, error) {
+	base := make(map[string]interface{})
+	for _, file := range simple.baseFiles {
+		layer, err := readConfigValues(file)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		mergeConfigValues(base, layer)
+	}
+
+	lower, err := simple.lowerLayer(base)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := readConfigValues(simple.file)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		file = make(map[string]interface{})
+	}
+
+	higher := make(map[string]interface{})
+	if simple.envPrefix != "" {
+		simple.applyEnv(higher)
+	}
+
+	for key, value := range simple.overrides {
+		setConfigValue(higher, key, value)
+	}
+
+	return configLayer(values, lower, higher, file), nil
+}
+
+// lowerLayer gets the values of the object decoded without the config file, with the defaults and the base files
+func (simple *SimpleConfig) lowerLayer(base map[string]interface{}) (map[string]interface{}, error) {
+	current := simple.GetObj()
+	if current == nil {
+		return base, nil
+	}
+
+	data, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := reflect.New(reflect.TypeOf(current).Elem()).Interface()
+	if len(simple.defaults) > 0 {
+		json.Unmarshal(simple.defaults, obj)
+	}
+
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, err
+	}
+
+	// applies the default tags, the object without the config file doesn't need to be valid
+	ValidateConfig(obj)
+
+	if data, err = json.Marshal(obj); err != nil {
+		return nil, err
+	}
+
+	lower := make(map[string]interface{})
+	if err := json.Unmarshal(data, &lower); err != nil {
+		return nil, err
+	}
+
+	return lower, nil
+}
+
+// configLayer gets the values that differ from the lower layer or that are on the file,
+// the keys of the higher layer keep the values of the file and the unknown keys of the file are kept
+func configLayer(values, lower, higher, file map[string]interface{}) map[string]interface{} {
+	layer := make(map[string]interface{})
+
+	for key, value := range values {
+		fileValue, inFile := file[key]
+		higherValue, inHigher := higher[key]
+		nested, isMap := value.(map[string]interface{})
+		higherMap, isHigherMap := higherValue.(map[string]interface{})
+
+		if inHigher && !(isMap && isHigherMap) {
+			if inFile {
+				layer[key] = fileValue
+			}
+			continue
+		}
+
+		if isMap {
+			lowerMap, _ := lower[key].(map[string]interface{})
+			fileMap, _ := fileValue.(map[string]interface{})
+
+			if nestedLayer := configLayer(nested, lowerMap, higherMap, fileMap); len(nestedLayer) > 0 || inFile {
+				layer[key] = nestedLayer
+			}
+			continue
+		}
+
+		if inFile || !reflect.DeepEqual(value, lower[key]) {
+			layer[key] = value
+		}
+	}
+
+	for key, value := range file {
+		if _, exists := values[key]; !exists {
+			layer[key] = value
+		}
+	}
+
+	return layer
+}
+
+// applyEnv sets the values of the environment variables with the prefix.
+// the names are matched with the known keys (the ones of the layers and of the object),
+// so that the keys with underscores are kept, the other ones are split on each underscore
+func (simple *SimpleConfig) applyEnv(values map[string]interface{}) {
+	prefix := strings.ToUpper(simple.envPrefix) + "_"
+
+	known := make(map[string]interface{})
+	if obj := simple.GetObj(); obj != nil {
+		if data, err := json.Marshal(obj); err == nil {
+			template := make(map[string]interface{})
+			if json.Unmarshal(data, &template) == nil {
+				flattenConfigValues("", template, known)
+			}
+		}
+	}
+	flattenConfigValues("", values, known)
+
+	byEnvName := make(map[string]string, len(known))
+	for key := range known {
+		byEnvName[strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))] = key
+	}
+
+	environ := os.Environ()
+	sort.Strings(environ)
+
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, prefix) || name == prefix {
+			continue
+		}
+
+		name = strings.TrimPrefix(name, prefix)
+		key, exists := byEnvName[name]
+		if !exists {
+			key = strings.ToLower(strings.ReplaceAll(name, "_", "."))
+		}
+
+		setConfigValue(values, key, convertEnvValue(value, known[key]))
+	}
+}
+
+// readConfigValues reads a config file into a map, decoded by its extension
+func readConfigValues(file string) (map[string]interface{}, error) {
+	data, err := ReadFile(file, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeConfigValues(GetConfigFormat(file), data)
+}
+
+// mergeConfigValues merges the source into the destination, the maps are merged and the other values replaced
+func mergeConfigValues(destination, source map[string]interface{}) {
+	for key, value := range source {
+		sourceMap, isSourceMap := value.(map[string]interface{})
+		destinationMap, isDestinationMap := destination[key].(map[string]interface{})
+
+		if isSourceMap && isDestinationMap {
+			mergeConfigValues(destinationMap, sourceMap)
+			continue
+		}
+
+		destination[key] = value
+	}
+}
+
+// setConfigValue sets the value of the key, with the levels separated by dots
+func setConfigValue(values map[string]interface{}, key string, value interface{}) {
+	levels := strings.Split(key, ".")
+
+	current := values
+	for _, level := range levels[:len(levels)-1] {
+		next, ok := current[level].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[level] = next
+		}
+		current = next
+	}
+
+	current[levels[len(levels)-1]] = value
+}
+
+// lookupConfigValue gets the value of the key, with the levels separated by dots and the indexes of the lists as levels.
+// the keys are matched ignoring the case, as the other getters of the config
+func lookupConfigValue(value interface{}, key string) (interface{}, bool) {
+	if key == "" {
+		return value, true
+	}
+
+	for _, level := range strings.Split(key, ".") {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			item, exists := typed[level]
+			if !exists {
+				for name, candidate := range typed {
+					if strings.EqualFold(name, level) {
+						item, exists = candidate, true
+						break
+					}
+				}
+			}
+
+			if !exists {
+				return nil, false
+			}
+			value = item
+		case []interface{}:
+			i, err := strconv.Atoi(level)
+			if err != nil || i < 0 || i >= len(typed) {
+				return nil, false
+			}
+			value = typed[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// flattenConfigValues collects the values by key, with the levels separated by dots
+func flattenConfigValues(prefix string, values map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenConfigValues(key, nested, flat)
+			continue
+		}
+
+		flat[key] = value
+	}
+}
+
+// convertEnvValue converts the value of an environment variable to the type of the current value of the key
+func convertEnvValue(value string, current interface{}) interface{} {
+	switch current.(type) {
+	case string:
+		return value
+	case bool:
+		if converted, err := strconv.ParseBool(value); err == nil {
+			return converted
+		}
+	case float64:
+		if converted, err := strconv.ParseFloat(value, 64); err == nil {
+			return converted
+		}
+	case []interface{}:
+		var converted []interface{}
+		if err := json.Unmarshal([]byte(value), &converted); err == nil {
+			return converted
+		}
+
+		converted = make([]interface{}, 0)
+		for _, item := range strings.Split(value, ",") {
+			converted = append(converted, strings.TrimSpace(item))
+		}
+		return converted
+	default:
+		var converted interface{}
+		if err := json.Unmarshal([]byte(value), &converted); err == nil {
+			return converted
+		}
+	}
+
+	return value
+}
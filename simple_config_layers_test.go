@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// layeredConfig has a field set last by each layer
+type layeredConfig struct {
+	Default  string `json:"default"`
+	Base     string `json:"base"`
+	File     string `json:"file"`
+	Env      string `json:"env"`
+	Override string `json:"override"`
+	Log      struct {
+		MaxSize int  `json:"max_size"`
+		Enabled bool `json:"enabled"`
+	} `json:"log"`
+}
+
+func TestConfigLayersPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	file := filepath.Join(dir, "app.json")
+
+	if err := os.WriteFile(base, []byte("base: base\nfile: base\nenv: base\noverride: base\nlog:\n  max_size: 1\n"), 0644); err != nil {
+		t.Fatalf("error writing the base file: %s", err)
+	}
+
+	if err := os.WriteFile(file, []byte(`{"file": "file", "env": "file", "override": "file", "log": {"enabled": false}}`), 0644); err != nil {
+		t.Fatalf("error writing the config file: %s", err)
+	}
+
+	t.Setenv("APP_ENV", "env")
+	t.Setenv("APP_OVERRIDE", "env")
+	t.Setenv("APP_LOG_MAX_SIZE", "10")
+	t.Setenv("APP_LOG_ENABLED", "true")
+
+	obj := &layeredConfig{Default: "default", Base: "default", File: "default", Env: "default", Override: "default"}
+	config, err := NewSimpleConfig(file, obj,
+		WithBaseConfigFile(base),
+		WithBaseConfigFile(filepath.Join(dir, "missing.json")),
+		WithEnvPrefix("app"),
+		WithOverrides(map[string]interface{}{"override": "override"}))
+	if err != nil {
+		t.Fatalf("error loading the config: %s", err)
+	}
+
+	tests := []struct {
+		key      string
+		value    string
+		expected string
+	}{
+		{key: "default", value: obj.Default, expected: "default"},
+		{key: "base", value: obj.Base, expected: "base"},
+		{key: "file", value: obj.File, expected: "file"},
+		{key: "env", value: obj.Env, expected: "env"},
+		{key: "override", value: obj.Override, expected: "override"},
+	}
+
+	for _, test := range tests {
+		if test.value != test.expected {
+			t.Errorf("the %s field is %s, expected %s", test.key, test.value, test.expected)
+		}
+	}
+
+	if obj.Log.MaxSize != 10 || !obj.Log.Enabled {
+		t.Errorf("the log is %+v, expected the max size and enabled of the environment, with the keys with underscores kept", obj.Log)
+	}
+
+	if value := config.GetString("override"); value != "override" {
+		t.Errorf("the override key is %s, expected override", value)
+	}
+}
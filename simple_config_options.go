@@ -0,0 +1,80 @@
+package manager
+
+// ConfigOption ...
+type ConfigOption func(simple *SimpleConfig)
+
+// Reconfigure ...
+func (simple *SimpleConfig) Reconfigure(options ...ConfigOption) {
+	for _, option := range options {
+		option(simple)
+	}
+}
+
+// WithBaseConfigFile adds a file loaded before the config file, so that the config file overrides its values.
+// the base files are optional and skipped when they don't exist
+func WithBaseConfigFile(file string) ConfigOption {
+	return func(simple *SimpleConfig) {
+		simple.baseFiles = append(simple.baseFiles, file)
+	}
+}
+
+// WithEnvPrefix overrides the config values with the environment variables with the prefix,
+// the key is the rest of the name separated by underscores (ex: PREFIX_MANAGER_LOG_LEVEL sets manager.log.level)
+func WithEnvPrefix(prefix string) ConfigOption {
+	return func(simple *SimpleConfig) {
+		simple.envPrefix = prefix
+	}
+}
+
+// WithOverrides overrides the config values, after all the other layers, by key (ex: manager.log.level)
+func WithOverrides(overrides map[string]interface{}) ConfigOption {
+	return func(simple *SimpleConfig) {
+		if simple.overrides == nil {
+			simple.overrides = make(map[string]interface{})
+		}
+
+		for key, value := range overrides {
+			simple.overrides[key] = value
+		}
+	}
+}
+
+// WithValidator adds a validation of the decoded config object, a reload with an invalid configuration is rejected
+func WithValidator(validator func(obj interface{}) error) ConfigOption {
+	return func(simple *SimpleConfig) {
+		simple.validators = append(simple.validators, validator)
+	}
+}
+
+// WithWatch reloads the configuration when the config file or the base files change
+func WithWatch() ConfigOption {
+	return func(simple *SimpleConfig) {
+		simple.watch = true
+	}
+}
+
+// WithSecretResolver adds the resolver of the secret references of the scheme (ex: ${vault:db/password}),
+// with precedence over the resolvers registered with RegisterSecretResolver
+func WithSecretResolver(scheme string, resolver ISecretResolver) ConfigOption {
+	return func(simple *SimpleConfig) {
+		if simple.resolvers == nil {
+			simple.resolvers = make(map[string]ISecretResolver)
+		}
+
+		simple.resolvers[scheme] = resolver
+	}
+}
+
+// WithHistory records the versions of the config file saved on the store
+func WithHistory(store IConfigHistoryStore) ConfigOption {
+	return func(simple *SimpleConfig) {
+		simple.history = store
+	}
+}
+
+// WithFileHistory records the versions of the config file saved on a file alongside it, with the .history extension
+func WithFileHistory() ConfigOption {
+	return func(simple *SimpleConfig) {
+		simple.history = NewFileConfigHistoryStore(resolveFile(simple.file) + ".history")
+	}
+}
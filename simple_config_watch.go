@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is the time waited after a file change, so that the writes of a save are reloaded once
+const watchDebounce = 100 * time.Millisecond
+
+// Watch reloads the configuration when the config file or the base files change.
+// when the new configuration is invalid, the error is logged and the current configuration is kept
+func (simple *SimpleConfig) Watch() error {
+	simple.mux.Lock()
+	defer simple.mux.Unlock()
+
+	if simple.watcher != nil {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// the directories are watched, so that the files replaced by the editors are still watched
+	files := make(map[string]bool)
+	directories := make(map[string]bool)
+	for _, name := range append(append([]string{}, simple.baseFiles...), simple.file) {
+		file, err := filepath.Abs(resolveFile(name))
+		if err != nil {
+			watcher.Close()
+			return err
+		}
+
+		files[file] = true
+		directory := filepath.Dir(file)
+		if directories[directory] {
+			continue
+		}
+
+		if err := watcher.Add(directory); err != nil {
+			if name == simple.file {
+				watcher.Close()
+				return err
+			}
+			continue
+		}
+		directories[directory] = true
+	}
+
+	simple.watcher = watcher
+	go simple.watchFiles(watcher, files)
+
+	return nil
+}
+
+// StopWatch ...
+func (simple *SimpleConfig) StopWatch() error {
+	simple.mux.Lock()
+	watcher := simple.watcher
+	simple.watcher = nil
+	simple.mux.Unlock()
+
+	if watcher == nil {
+		return nil
+	}
+
+	return watcher.Close()
+}
+
+// watchFiles reloads the configuration after the changes of the files
+func (simple *SimpleConfig) watchFiles(watcher *fsnotify.Watcher, files map[string]bool) {
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if !files[filepath.Clean(event.Name)] || event.Op == fsnotify.Chmod {
+				continue
+			}
+			timer.Reset(watchDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			simple.logger.Errorf("error watching the config file [ file: %s ]: %s", simple.file, err)
+
+		case <-timer.C:
+			simple.logger.Infof("reloading the config file [ file: %s ]", simple.file)
+			if err := simple.Reload(); err != nil {
+				simple.logger.Errorf("error reloading the config file, keeping the current configuration [ file: %s ]: %s", simple.file, err)
+			}
+		}
+	}
+}
@@ -1,11 +1,10 @@
 package manager
 
 import (
+	"context"
 	"database/sql"
 	"github.com/joaosoft/logger"
 
-	"sync"
-
 	_ "github.com/go-sql-driver/mysql" // mysql driver
 	_ "github.com/lib/pq"              // postgres driver
 )
@@ -14,8 +13,8 @@ import (
 type SimpleDB struct {
 	*sql.DB
 	logger logger.ILogger
-	config  *DBConfig
-	started bool
+	config *DBConfig
+	state  *StateMachine
 }
 
 // NewSimpleDB ...
@@ -23,6 +22,7 @@ func (manager *Manager) NewSimpleDB(config *DBConfig) IDB {
 	return &SimpleDB{
 		config: config,
 		logger: manager.logger,
+		state:  NewStateMachine(),
 	}
 }
 
@@ -32,58 +32,43 @@ func (db *SimpleDB) Get() *sql.DB {
 }
 
 // Start ...
-func (db *SimpleDB) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+func (db *SimpleDB) Start(ctx context.Context) (err error) {
+	if !db.state.start() {
+		return nil
 	}
+	defer func() { db.state.started(err) }()
 
-	defer wg.Done()
-
-	if db.started {
-		return nil
+	conn, err := db.config.Connect()
+	if err != nil {
+		return err
 	}
 
-	if conn, err := db.config.Connect(); err != nil {
+	if err = conn.PingContext(ctx); err != nil {
+		conn.Close()
 		return err
-	} else {
-		db.DB = conn
-		db.started = true
 	}
 
+	db.DB = conn
+
 	return nil
 }
 
 // Stop ...
-func (db *SimpleDB) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !db.started {
+func (db *SimpleDB) Stop(ctx context.Context) (err error) {
+	if !db.state.stop() {
 		return nil
 	}
+	defer func() { db.state.stopped(err) }()
 
-	if err := db.Close(); err != nil {
-		return err
-	}
-	db.started = false
-
-	return nil
+	return db.Close()
 }
 
 // Started ...
 func (db *SimpleDB) Started() bool {
-	return db.started
+	return db.state.Started()
+}
+
+// HealthCheck ...
+func (db *SimpleDB) HealthCheck(ctx context.Context) error {
+	return NewSQLHealthCheck(db.DB).HealthCheck(ctx)
 }
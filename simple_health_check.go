@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/streadway/amqp"
+)
+
+// NewSQLHealthCheck checks the database with a ping
+func NewSQLHealthCheck(db *sql.DB) IHealthChecker {
+	return HealthCheckFunc(func(ctx context.Context) error {
+		if db == nil {
+			return fmt.Errorf("database not connected")
+		}
+		return db.PingContext(ctx)
+	})
+}
+
+// NewRedisHealthCheck checks redis with a ping
+func NewRedisHealthCheck(redis IRedis) IHealthChecker {
+	return HealthCheckFunc(func(ctx context.Context) error {
+		return redis.Ping()
+	})
+}
+
+// NewAMQPHealthCheck checks the state of the amqp connection
+func NewAMQPHealthCheck(connection *amqp.Connection) IHealthChecker {
+	return HealthCheckFunc(func(ctx context.Context) error {
+		if connection == nil || connection.IsClosed() {
+			return fmt.Errorf("amqp connection closed")
+		}
+		return nil
+	})
+}
+
+// NewNSQHealthCheck checks the nsq producer with a ping
+func NewNSQHealthCheck(producer INSQProducer) IHealthChecker {
+	return HealthCheckFunc(func(ctx context.Context) error {
+		return producer.Ping()
+	})
+}
+
+// NewTCPHealthCheck checks that the address accepts connections
+func NewTCPHealthCheck(address string) IHealthChecker {
+	if strings.HasPrefix(address, ":") {
+		address = "127.0.0.1" + address
+	}
+
+	return HealthCheckFunc(func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
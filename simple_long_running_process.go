@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/joaosoft/logger"
+)
+
+// LongRunningProcessOption ...
+type LongRunningProcessOption func(process *LongRunningProcess)
+
+// WithReadySignal makes the start wait until the function calls SignalReady with its context,
+// instead of reporting the process as ready immediately
+func WithReadySignal() LongRunningProcessOption {
+	return func(process *LongRunningProcess) {
+		process.waitReady = true
+	}
+}
+
+type readySignalKey struct{}
+
+// SignalReady reports that the long running process owning the context is ready
+func SignalReady(ctx context.Context) {
+	if ready, ok := ctx.Value(readySignalKey{}).(func()); ok {
+		ready()
+	}
+}
+
+// LongRunningProcess runs a function in its own goroutine until it returns or the process is stopped
+type LongRunningProcess struct {
+	function  func(ctx context.Context) error
+	waitReady bool
+	logger    logger.ILogger
+	run       *processRun
+	exited    chan error
+	state     *StateMachine
+	mux       sync.Mutex
+}
+
+// processRun ...
+type processRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// NewLongRunningProcess creates a process that runs the function in its own goroutine,
+// the context given to the function is canceled when the process is stopped
+func (manager *Manager) NewLongRunningProcess(function func(ctx context.Context) error, options ...LongRunningProcessOption) IProcess {
+	process := &LongRunningProcess{
+		function: function,
+		logger:   manager.logger,
+		exited:   make(chan error, 1),
+		state:    NewStateMachine(),
+	}
+
+	for _, option := range options {
+		option(process)
+	}
+
+	return process
+}
+
+// Start ...
+func (process *LongRunningProcess) Start(ctx context.Context) (err error) {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	if !process.state.start() {
+		return nil
+	}
+	defer func() { process.state.started(err) }()
+
+	var once sync.Once
+	ready := make(chan struct{})
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	runCtx = context.WithValue(runCtx, readySignalKey{}, func() {
+		once.Do(func() { close(ready) })
+	})
+
+	run := &processRun{cancel: cancel, done: make(chan struct{})}
+	process.run = run
+
+	go process.execute(runCtx, run)
+
+	if process.waitReady {
+		select {
+		case <-ready:
+		case <-run.done:
+			if run.err != nil {
+				return run.err
+			}
+			return fmt.Errorf("process ended before being ready")
+		case <-ctx.Done():
+			cancel()
+			<-run.done
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// Stop cancels the context of the function and waits for it to return
+func (process *LongRunningProcess) Stop(ctx context.Context) (err error) {
+	process.mux.Lock()
+	if !process.state.stop() {
+		process.mux.Unlock()
+		return nil
+	}
+
+	run := process.run
+	process.mux.Unlock()
+	defer func() { process.state.stopped(err) }()
+
+	run.cancel()
+
+	select {
+	case <-run.done:
+		if errors.Is(run.err, context.Canceled) {
+			return nil
+		}
+		return run.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Started ...
+func (process *LongRunningProcess) Started() bool {
+	return process.state.Started()
+}
+
+// Exited ...
+func (process *LongRunningProcess) Exited() <-chan error {
+	return process.exited
+}
+
+// execute runs the function and notifies when it ends without being stopped
+func (process *LongRunningProcess) execute(ctx context.Context, run *processRun) {
+	defer run.cancel()
+
+	run.err = process.function(ctx)
+	close(run.done)
+
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	if !process.state.Started() || process.run != run {
+		return
+	}
+
+	if run.err != nil {
+		process.state.Transition(StateFailed, run.err)
+		process.logger.Errorf("long running process ended with error: %s", run.err)
+	} else {
+		process.state.Transition(StateStopped, nil)
+		process.logger.Infof("long running process ended")
+	}
+
+	select {
+	case process.exited <- run.err:
+	default:
+	}
+}
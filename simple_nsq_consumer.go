@@ -1,12 +1,11 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"github.com/joaosoft/logger"
 	"time"
 
-	"sync"
-
 	"github.com/nsqio/go-nsq"
 )
 
@@ -14,9 +13,9 @@ import (
 type SimpleNSQConsumer struct {
 	client  *nsq.Consumer
 	handler INSQHandler
-	logger logger.ILogger
+	logger  logger.ILogger
 	config  *NSQConfig
-	started bool
+	state   *StateMachine
 }
 
 // NewSimpleNSQConsumer ...
@@ -31,15 +30,17 @@ func (manager *Manager) NewSimpleNSQConsumer(config *NSQConfig, handler INSQHand
 	nsqConfig.ReadTimeout = 120 * time.Second
 
 	nsqConsumer, err := nsq.NewConsumer(config.Topic, config.Channel, nsqConfig)
-	nsqConsumer.AddHandler(handler)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	nsqConsumer.AddHandler(handler)
 
 	consumer := &SimpleNSQConsumer{
 		client:  nsqConsumer,
 		config:  config,
 		handler: handler,
+		logger:  manager.logger,
+		state:   NewStateMachine(),
 	}
 
 	manager.logger.Infof("nsq consumer, consumer [ topic: %s, channel: %s ] created", config.Topic, config.Channel)
@@ -60,25 +61,15 @@ func (consumer *SimpleNSQConsumer) HandleMessage(message *nsq.Message) error {
 
 // Stop ...
 func (consumer *SimpleNSQConsumer) Started() bool {
-	return consumer.started
+	return consumer.state.Started()
 }
 
 // Start ...
-func (consumer *SimpleNSQConsumer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if consumer.started {
+func (consumer *SimpleNSQConsumer) Start(ctx context.Context) (err error) {
+	if !consumer.state.start() {
 		return nil
 	}
+	defer func() { consumer.state.started(err) }()
 
 	if consumer.handler == nil {
 		return fmt.Errorf("nsq consumer, no handler configured")
@@ -104,36 +95,22 @@ func (consumer *SimpleNSQConsumer) Start(waitGroup ...*sync.WaitGroup) error {
 		}
 	}
 
-	if !consumer.started {
-		panic("nsq consumer, failed to start consumer")
-	}
-
-	consumer.started = true
-
-	<-consumer.client.StopChan
-
 	return nil
 }
 
 // Stop ...
-func (consumer *SimpleNSQConsumer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !consumer.started {
+func (consumer *SimpleNSQConsumer) Stop(ctx context.Context) (err error) {
+	if !consumer.state.stop() {
 		return nil
 	}
+	defer func() { consumer.state.stopped(err) }()
 
 	consumer.client.Stop()
-	consumer.started = false
 
-	return nil
+	select {
+	case <-consumer.client.StopChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
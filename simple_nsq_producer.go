@@ -1,21 +1,20 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"github.com/joaosoft/logger"
 	"time"
 
-	"sync"
-
 	"github.com/nsqio/go-nsq"
 )
 
 // Producer ...
 type SimpleNSQProducer struct {
-	client  *nsq.Producer
+	client *nsq.Producer
 	logger logger.ILogger
-	config  *NSQConfig
-	started bool
+	config *NSQConfig
+	state  *StateMachine
 }
 
 // NewSimpleNSQProducer ...
@@ -45,6 +44,7 @@ func (manager *Manager) NewSimpleNSQProducer(config *NSQConfig) (INSQProducer, e
 		client: nsqProducer,
 		config: config,
 		logger: manager.logger,
+		state:  NewStateMachine(),
 	}
 
 	return producer, nil
@@ -64,53 +64,36 @@ func (producer *SimpleNSQProducer) Publish(topic string, body []byte, maxRetries
 }
 
 // Start ...
-func (producer *SimpleNSQProducer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if producer.started {
+func (producer *SimpleNSQProducer) Start(ctx context.Context) error {
+	if !producer.state.start() {
 		return nil
 	}
 
-	producer.started = true
+	producer.state.started(nil)
 
 	return nil
 }
 
 // Stop ...
-func (producer *SimpleNSQProducer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !producer.started {
+func (producer *SimpleNSQProducer) Stop(ctx context.Context) error {
+	if !producer.state.stop() {
 		return nil
 	}
 
 	producer.client.Stop()
-	producer.started = false
+	producer.state.stopped(nil)
 
 	return nil
 }
 
-// Start ...
+// Started ...
 func (producer *SimpleNSQProducer) Started() bool {
-	return true
+	return producer.state.Started()
+}
+
+// HealthCheck ...
+func (producer *SimpleNSQProducer) HealthCheck(ctx context.Context) error {
+	return NewNSQHealthCheck(producer).HealthCheck(ctx)
 }
 
 // Ping ...
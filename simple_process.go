@@ -1,74 +1,64 @@
 package manager
 
 import (
+	"context"
 	"github.com/joaosoft/logger"
-	"sync"
 )
 
 // SimpleProcess ...
 type SimpleProcess struct {
 	function func() error
-	logger logger.ILogger
-	started  bool
+	logger   logger.ILogger
+	state    *StateMachine
 }
 
 // NewSimpleProcess...
 func (manager *Manager) NewSimpleProcess(function func() error) IProcess {
 	return &SimpleProcess{
 		function: function,
-		logger: manager.logger,
+		logger:   manager.logger,
+		state:    NewStateMachine(),
 	}
 }
 
 // Start ...
-func (process *SimpleProcess) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if process.started {
+func (process *SimpleProcess) Start(ctx context.Context) (err error) {
+	if !process.state.start() {
 		return nil
 	}
-
-	if err := process.function(); err != nil {
-		return err
+	defer func() { process.state.started(err) }()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- process.function()
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 
-	process.started = true
-
+	// the function already ended, so the process is completed and isn't notified as an exit to restart,
+	// its failures are start failures
 	return nil
 }
 
 // Stop ...
-func (process *SimpleProcess) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !process.started {
+func (process *SimpleProcess) Stop(ctx context.Context) error {
+	if !process.state.stop() {
 		return nil
 	}
 
-	process.started = false
+	process.state.stopped(nil)
 
 	return nil
 }
 
 // Started ...
 func (process *SimpleProcess) Started() bool {
-	return process.started
+	return process.state.Started()
 }
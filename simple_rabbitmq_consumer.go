@@ -1,9 +1,12 @@
 package manager
 
 import (
-	"github.com/joaosoft/logger"
+	"context"
+	"fmt"
 	"sync"
 
+	"github.com/joaosoft/logger"
+
 	"github.com/streadway/amqp"
 )
 
@@ -15,9 +18,12 @@ type SimpleRabbitmqConsumer struct {
 	bindingKey string
 	tag        string
 	handler    RabbitmqHandler
-	logger logger.ILogger
+	logger     logger.ILogger
 	done       chan error
-	started    bool
+	stopping   chan struct{}
+	exited     chan error
+	state      *StateMachine
+	mux        sync.Mutex
 }
 
 func (manager *Manager) NewSimpleRabbitmqConsumer(config *RabbitmqConfig, queue, bindingKey, tag string, handler RabbitmqHandler) (*SimpleRabbitmqConsumer, error) {
@@ -29,43 +35,36 @@ func (manager *Manager) NewSimpleRabbitmqConsumer(config *RabbitmqConfig, queue,
 		bindingKey: bindingKey,
 		tag:        tag,
 		handler:    handler,
-		logger: manager.logger,
-		done:       make(chan error),
+		logger:     manager.logger,
+		done:       make(chan error, 1),
+		exited:     make(chan error, 1),
+		state:      NewStateMachine(),
 	}
 
 	return consumer, nil
 }
 
-func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (consumer *SimpleRabbitmqConsumer) Start(ctx context.Context) (err error) {
+	consumer.mux.Lock()
+	defer consumer.mux.Unlock()
 
-	if consumer.started {
+	if !consumer.state.start() {
 		return nil
 	}
+	defer func() { consumer.state.started(err) }()
 
-	var err error
 	consumer.connection, err = consumer.config.Connect()
 	if err != nil {
 		err = consumer.logger.Errorf("dial: %s", err).ToError()
 		return err
 	}
 
-	defer func(err error) {
-		if err != nil {
-			if consumer.connection != nil {
-				consumer.connection.Close()
-			}
+	// the connection is closed when any of the next steps fails, with the error of the start
+	defer func() {
+		if err != nil && consumer.connection != nil {
+			consumer.connection.Close()
 		}
-	}(err)
+	}()
 
 	consumer.logger.Infof("got connection, getting channel")
 	consumer.channel, err = consumer.connection.Channel()
@@ -78,11 +77,11 @@ func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) erro
 	if err = consumer.channel.ExchangeDeclare(
 		consumer.config.Exchange,     // name of the exchange
 		consumer.config.ExchangeType, // type
-		true,  // durable
-		false, // delete when complete
-		false, // internal
-		false, // noWait
-		nil,   // arguments
+		true,                         // durable
+		false,                        // delete when complete
+		false,                        // internal
+		false,                        // noWait
+		nil,                          // arguments
 	); err != nil {
 		err = consumer.logger.Errorf("exchange declare: %s", err).ToError()
 		return err
@@ -108,8 +107,8 @@ func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) erro
 		consumer.queue,           // name of the queue
 		consumer.bindingKey,      // bindingKey
 		consumer.config.Exchange, // sourceExchange
-		false, // noWait
-		nil,   // arguments
+		false,                    // noWait
+		nil,                      // arguments
 	); err != nil {
 		err = consumer.logger.Errorf("queue bind: %s", err).ToError()
 		return err
@@ -130,33 +129,32 @@ func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) erro
 		return err
 	}
 
-	go consumer.handle(deliveries, consumer.done)
-
-	consumer.started = true
+	consumer.stopping = make(chan struct{})
+	go consumer.handle(deliveries, consumer.stopping)
 
 	return nil
 }
 
 func (consumer *SimpleRabbitmqConsumer) Started() bool {
-	return consumer.started
+	return consumer.state.Started()
 }
 
-func (consumer *SimpleRabbitmqConsumer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+// HealthCheck ...
+func (consumer *SimpleRabbitmqConsumer) HealthCheck(ctx context.Context) error {
+	return NewAMQPHealthCheck(consumer.connection).HealthCheck(ctx)
+}
 
-	if !consumer.started {
+func (consumer *SimpleRabbitmqConsumer) Stop(ctx context.Context) (err error) {
+	consumer.mux.Lock()
+	if !consumer.state.stop() {
+		consumer.mux.Unlock()
 		return nil
 	}
 
+	close(consumer.stopping)
+	consumer.mux.Unlock()
+	defer func() { consumer.state.stopped(err) }()
+
 	// will close() the deliveries channel
 	if err := consumer.channel.Cancel(consumer.tag, true); err != nil {
 		err = consumer.logger.Errorf("consumer cancel failed: %s", err).ToError()
@@ -170,14 +168,21 @@ func (consumer *SimpleRabbitmqConsumer) Stop(waitGroup ...*sync.WaitGroup) error
 
 	consumer.logger.Infof("AMQP shutdown OK")
 
-	consumer.started = false
-
 	// wait for handle() to exit
-	return <-consumer.done
+	select {
+	case err := <-consumer.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
+// Exited ...
+func (consumer *SimpleRabbitmqConsumer) Exited() <-chan error {
+	return consumer.exited
 }
 
-func (consumer *SimpleRabbitmqConsumer) handle(deliveries <-chan amqp.Delivery, done chan error) {
+func (consumer *SimpleRabbitmqConsumer) handle(deliveries <-chan amqp.Delivery, stopping chan struct{}) {
 	for delivery := range deliveries {
 		if err := consumer.handler(delivery); err != nil {
 			delivery.Ack(false)
@@ -188,5 +193,25 @@ func (consumer *SimpleRabbitmqConsumer) handle(deliveries <-chan amqp.Delivery,
 	}
 
 	consumer.logger.Infof("handle: deliveries channel closed")
-	done <- nil
+
+	select {
+	case <-stopping:
+		// buffered, so that it doesn't block when the stop already timed out
+		consumer.done <- nil
+	default:
+		// the deliveries channel was closed without a stop, so the consumer is dead
+		err := fmt.Errorf("rabbitmq consumer, deliveries channel closed [ queue: %s ]", consumer.queue)
+		consumer.mux.Lock()
+		consumer.state.Transition(StateFailed, err)
+		consumer.mux.Unlock()
+
+		if consumer.connection != nil {
+			consumer.connection.Close()
+		}
+
+		select {
+		case consumer.exited <- err:
+		default:
+		}
+	}
 }
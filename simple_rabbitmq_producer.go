@@ -1,11 +1,10 @@
 package manager
 
 import (
+	"context"
 	"github.com/joaosoft/logger"
 	"time"
 
-	"sync"
-
 	"github.com/streadway/amqp"
 )
 
@@ -14,47 +13,36 @@ type SimpleRabbitmqProducer struct {
 	connection *amqp.Connection
 	channel    *amqp.Channel
 	tag        string
-	logger logger.ILogger
-	started    bool
+	logger     logger.ILogger
+	state      *StateMachine
 }
 
 func (manager *Manager) NewSimpleRabbitmqProducer(config *RabbitmqConfig) (*SimpleRabbitmqProducer, error) {
 	return &SimpleRabbitmqProducer{
 		config: config,
 		logger: manager.logger,
+		state:  NewStateMachine(),
 	}, nil
 }
 
-func (producer *SimpleRabbitmqProducer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if producer.started {
+func (producer *SimpleRabbitmqProducer) Start(ctx context.Context) (err error) {
+	if !producer.state.start() {
 		return nil
 	}
+	defer func() { producer.state.started(err) }()
 
-	var err error
 	producer.connection, err = producer.config.Connect()
 	if err != nil {
 		err = producer.logger.Errorf("dial: %s", err).ToError()
 		return err
 	}
 
-	defer func(err error) {
-		if err != nil {
-			if producer.connection != nil {
-				producer.connection.Close()
-			}
+	// the connection is closed when any of the next steps fails, with the error of the start
+	defer func() {
+		if err != nil && producer.connection != nil {
+			producer.connection.Close()
 		}
-	}(err)
+	}()
 
 	producer.logger.Infof("got connection, getting channel")
 	if producer.channel, err = producer.connection.Channel(); err != nil {
@@ -66,41 +54,33 @@ func (producer *SimpleRabbitmqProducer) Start(waitGroup ...*sync.WaitGroup) erro
 	if err = producer.channel.ExchangeDeclare(
 		producer.config.Exchange,     // name
 		producer.config.ExchangeType, // type
-		true,  // durable
-		false, // auto-deleted
-		false, // internal
-		false, // noWait
-		nil,   // arguments
+		true,                         // durable
+		false,                        // auto-deleted
+		false,                        // internal
+		false,                        // noWait
+		nil,                          // arguments
 	); err != nil {
 		err = producer.logger.Errorf("exchange declare: %s", err).ToError()
 		return err
 	}
 
-	producer.started = true
-
 	return nil
 }
 
 func (producer *SimpleRabbitmqProducer) Started() bool {
-	producer.started = false
-	return producer.started
+	return producer.state.Started()
 }
 
-func (producer *SimpleRabbitmqProducer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+// HealthCheck ...
+func (producer *SimpleRabbitmqProducer) HealthCheck(ctx context.Context) error {
+	return NewAMQPHealthCheck(producer.connection).HealthCheck(ctx)
+}
 
-	if !producer.started {
+func (producer *SimpleRabbitmqProducer) Stop(ctx context.Context) (err error) {
+	if !producer.state.stop() {
 		return nil
 	}
+	defer func() { producer.state.stopped(err) }()
 
 	// will close() the deliveries channel
 	if err := producer.channel.Cancel(producer.tag, true); err != nil {
@@ -114,7 +94,6 @@ func (producer *SimpleRabbitmqProducer) Stop(waitGroup ...*sync.WaitGroup) error
 	}
 
 	producer.logger.Infof("AMQP shutdown OK")
-	producer.started = false
 
 	return nil
 }
@@ -1,89 +1,67 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"github.com/joaosoft/logger"
 	"reflect"
 
 	"strings"
 
-	"sync"
-
 	"github.com/alphazero/Go-Redis"
 )
 
 // SimpleRedis ...
 type SimpleRedis struct {
-	client  redis.Client
-	config  *RedisConfig
+	client redis.Client
+	config *RedisConfig
 	logger logger.ILogger
-	started bool
+	state  *StateMachine
 }
 
 // NewSimpleRedis ...
 func (manager *Manager) NewSimpleRedis(config *RedisConfig) IRedis {
 	return &SimpleRedis{
 		config: config,
-		logger:manager.logger,
+		logger: manager.logger,
+		state:  NewStateMachine(),
 	}
 }
 
 // Start ...
-func (redis *SimpleRedis) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if redis.started {
+func (redis *SimpleRedis) Start(ctx context.Context) (err error) {
+	if !redis.state.start() {
 		return nil
 	}
+	defer func() { redis.state.started(err) }()
 
 	if conn, err := redis.config.Connect(); err != nil {
 		redis.logger.Error(err)
 		return err
 	} else {
 		redis.client = conn
-		redis.started = true
 	}
 	return nil
 }
 
 // Stop ...
-func (redis *SimpleRedis) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !redis.started {
+func (redis *SimpleRedis) Stop(ctx context.Context) (err error) {
+	if !redis.state.stop() {
 		return nil
 	}
+	defer func() { redis.state.stopped(err) }()
 
-	if err := redis.client.Quit(); err != nil {
-		return err
-	}
-
-	redis.started = false
-
-	return nil
+	return redis.client.Quit()
 }
 
 // Started ...
 func (redis *SimpleRedis) Started() bool {
-	return redis.started
+	return redis.state.Started()
+}
+
+// HealthCheck ...
+func (redis *SimpleRedis) HealthCheck(ctx context.Context) error {
+	return NewRedisHealthCheck(redis).HealthCheck(ctx)
 }
 
 // Action ...
@@ -369,6 +347,11 @@ func (redis *SimpleRedis) Lastsave() (int64, error) {
 	return redis.client.Lastsave()
 }
 
+// Subscribe subscribes the channel with a dedicated connection, returning the messages and the function to unsubscribe
+func (redis *SimpleRedis) Subscribe(channel string) (<-chan []byte, func() error, error) {
+	return redis.config.Subscribe(channel)
+}
+
 func (redis *SimpleRedis) Publish(channel string, message []byte) (int64, error) {
 	return redis.client.Publish(channel, message)
 }
@@ -0,0 +1,234 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+// ScheduledProcess runs a function on the activations of a schedule
+type ScheduledProcess struct {
+	schedule    ISchedule
+	function    func(ctx context.Context) error
+	overlap     OverlapPolicy
+	location    *time.Location
+	historySize int
+	history     []*ScheduledRun
+	nextRun     time.Time
+	running     int
+	queued      int
+	logger      logger.ILogger
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	state       *StateMachine
+	mux         sync.Mutex
+}
+
+// NewCronProcess creates a process that runs the function on the times matched by the cron expression
+func (manager *Manager) NewCronProcess(expression string, function func(ctx context.Context) error, options ...ScheduleOption) (*ScheduledProcess, error) {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.NewScheduledProcess(schedule, function, options...), nil
+}
+
+// NewIntervalProcess creates a process that runs the function on every interval, delayed by a random jitter
+func (manager *Manager) NewIntervalProcess(interval, jitter time.Duration, function func(ctx context.Context) error, options ...ScheduleOption) (*ScheduledProcess, error) {
+	schedule, err := NewIntervalSchedule(interval, jitter)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.NewScheduledProcess(schedule, function, options...), nil
+}
+
+// NewScheduledProcess ...
+func (manager *Manager) NewScheduledProcess(schedule ISchedule, function func(ctx context.Context) error, options ...ScheduleOption) *ScheduledProcess {
+	process := &ScheduledProcess{
+		schedule:    schedule,
+		function:    function,
+		overlap:     OverlapSkip,
+		location:    time.Local,
+		historySize: defaultScheduleHistorySize,
+		logger:      manager.logger,
+		state:       NewStateMachine(),
+	}
+
+	for _, option := range options {
+		option(process)
+	}
+
+	return process
+}
+
+// Start ...
+func (process *ScheduledProcess) Start(ctx context.Context) error {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	if !process.state.start() {
+		return nil
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	process.cancel = cancel
+	process.nextRun = process.schedule.Next(time.Now().In(process.location))
+	process.state.started(nil)
+
+	process.wg.Add(1)
+	go process.loop(runCtx, process.nextRun)
+
+	return nil
+}
+
+// Stop stops the schedule and waits for the running executions
+func (process *ScheduledProcess) Stop(ctx context.Context) (err error) {
+	process.mux.Lock()
+	if !process.state.stop() {
+		process.mux.Unlock()
+		return nil
+	}
+
+	process.nextRun = time.Time{}
+	process.queued = 0
+	process.cancel()
+	process.mux.Unlock()
+	defer func() { process.state.stopped(err) }()
+
+	done := make(chan struct{})
+	go func() {
+		process.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Started ...
+func (process *ScheduledProcess) Started() bool {
+	return process.state.Started()
+}
+
+// NextRun returns the time of the next run, zero when it isn't scheduled
+func (process *ScheduledProcess) NextRun() time.Time {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	return process.nextRun
+}
+
+// History returns the last runs, the oldest first
+func (process *ScheduledProcess) History() []ScheduledRun {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	history := make([]ScheduledRun, 0, len(process.history))
+	for _, run := range process.history {
+		history = append(history, *run)
+	}
+
+	return history
+}
+
+// loop waits for each activation of the schedule
+func (process *ScheduledProcess) loop(ctx context.Context, next time.Time) {
+	defer process.wg.Done()
+
+	for !next.IsZero() {
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		process.trigger(ctx, next)
+
+		process.mux.Lock()
+		next = process.schedule.Next(next.In(process.location))
+		if now := time.Now().In(process.location); next.Before(now) {
+			// the activations missed while the process was busy aren't recovered
+			next = process.schedule.Next(now)
+		}
+		process.nextRun = next
+		process.mux.Unlock()
+	}
+
+	process.logger.Warnf("scheduled process has no more runs")
+}
+
+// trigger runs the function applying the overlap policy
+func (process *ScheduledProcess) trigger(ctx context.Context, scheduledAt time.Time) {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	if process.running > 0 {
+		switch process.overlap {
+		case OverlapSkip:
+			process.logger.Warnf("skipping scheduled run, the previous one is still running [ scheduled at: %s ]", scheduledAt)
+			process.record(&ScheduledRun{ScheduledAt: scheduledAt, Skipped: true})
+			return
+		case OverlapQueue:
+			process.queued++
+			return
+		}
+	}
+
+	process.running++
+	process.wg.Add(1)
+	go process.execute(ctx, scheduledAt)
+}
+
+// execute runs the function, and then the queued runs
+func (process *ScheduledProcess) execute(ctx context.Context, scheduledAt time.Time) {
+	defer process.wg.Done()
+
+	for {
+		run := &ScheduledRun{ScheduledAt: scheduledAt, StartedAt: time.Now()}
+
+		err := process.function(ctx)
+
+		run.FinishedAt = time.Now()
+		run.Duration = run.FinishedAt.Sub(run.StartedAt)
+		if err != nil {
+			run.Error = err.Error()
+			process.logger.Errorf("error on scheduled run [ scheduled at: %s ]: %s", scheduledAt, err)
+		}
+
+		process.mux.Lock()
+		process.record(run)
+
+		if process.queued == 0 || ctx.Err() != nil {
+			process.running--
+			process.mux.Unlock()
+			return
+		}
+
+		process.queued--
+		scheduledAt = time.Now()
+		process.mux.Unlock()
+	}
+}
+
+// record adds the run to the history, dropping the oldest ones
+func (process *ScheduledProcess) record(run *ScheduledRun) {
+	if process.historySize <= 0 {
+		return
+	}
+
+	process.history = append(process.history, run)
+	if len(process.history) > process.historySize {
+		process.history = process.history[len(process.history)-process.historySize:]
+	}
+}
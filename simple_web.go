@@ -1,7 +1,7 @@
 package manager
 
 import (
-	"sync"
+	"context"
 
 	"github.com/joaosoft/logger"
 
@@ -10,10 +10,10 @@ import (
 
 // SimpleWebServer ...
 type SimpleWebServer struct {
-	server  *web.Server
-	host    string
-	logger  logger.ILogger
-	started bool
+	server *web.Server
+	host   string
+	logger logger.ILogger
+	state  *StateMachine
 }
 
 // NewSimpleWebServer...
@@ -21,7 +21,9 @@ func (manager *Manager) NewSimpleWebServer(host string) IWeb {
 	server, _ := web.NewServer(web.WithServerAddress(host))
 	return &SimpleWebServer{
 		server: server,
+		host:   host,
 		logger: manager.logger,
+		state:  NewStateMachine(),
 	}
 }
 
@@ -88,57 +90,35 @@ func (w *SimpleWebServer) AddFilter(pattern string, position string, middleware
 }
 
 // Start ...
-func (w *SimpleWebServer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if w.started {
+func (w *SimpleWebServer) Start(ctx context.Context) error {
+	if !w.state.start() {
 		return nil
 	}
 
 	go w.server.Start()
-	w.started = true
+	w.state.started(nil)
 
 	return nil
 }
 
 // Stop ...
-func (w *SimpleWebServer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !w.started {
+func (w *SimpleWebServer) Stop(ctx context.Context) (err error) {
+	if !w.state.stop() {
 		return nil
 	}
+	defer func() { w.state.stopped(err) }()
 
-	if err := w.server.Stop(); err != nil {
-		return err
-	}
-
-	w.started = false
-
-	return nil
+	return w.server.Stop()
 }
 
 // Started ...
 func (w *SimpleWebServer) Started() bool {
-	return w.started
+	return w.state.Started()
+}
+
+// HealthCheck ...
+func (w *SimpleWebServer) HealthCheck(ctx context.Context) error {
+	return NewTCPHealthCheck(w.host).HealthCheck(ctx)
 }
 
 // GetClient ...
@@ -1,7 +1,7 @@
 package manager
 
 import (
-	"sync"
+	"context"
 
 	"github.com/joaosoft/logger"
 
@@ -10,10 +10,10 @@ import (
 
 // SimpleWebEcho ...
 type SimpleWebEcho struct {
-	server  *echo.Echo
-	host    string
-	logger  logger.ILogger
-	started bool
+	server *echo.Echo
+	host   string
+	logger logger.ILogger
+	state  *StateMachine
 }
 
 // NewSimpleWebEcho...
@@ -25,6 +25,7 @@ func (manager *Manager) NewSimpleWebEcho(host string) IWeb {
 		server: e,
 		host:   host,
 		logger: manager.logger,
+		state:  NewStateMachine(),
 	}
 }
 
@@ -75,57 +76,35 @@ func (w *SimpleWebEcho) AddFilter(pattern string, position string, middleware Mi
 }
 
 // Start ...
-func (w *SimpleWebEcho) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if w.started {
+func (w *SimpleWebEcho) Start(ctx context.Context) error {
+	if !w.state.start() {
 		return nil
 	}
 
 	go w.server.Start(w.host)
-	w.started = true
+	w.state.started(nil)
 
 	return nil
 }
 
 // Stop ...
-func (w *SimpleWebEcho) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !w.started {
+func (w *SimpleWebEcho) Stop(ctx context.Context) (err error) {
+	if !w.state.stop() {
 		return nil
 	}
+	defer func() { w.state.stopped(err) }()
 
-	if err := w.server.Close(); err != nil {
-		return err
-	}
-
-	w.started = false
-
-	return nil
+	return w.server.Shutdown(ctx)
 }
 
 // Started ...
 func (w *SimpleWebEcho) Started() bool {
-	return w.started
+	return w.state.Started()
+}
+
+// HealthCheck ...
+func (w *SimpleWebEcho) HealthCheck(ctx context.Context) error {
+	return NewTCPHealthCheck(w.host).HealthCheck(ctx)
 }
 
 // GetClient ...
@@ -1,8 +1,8 @@
 package manager
 
 import (
+	"context"
 	"net/http"
-	"sync"
 
 	"github.com/joaosoft/logger"
 )
@@ -13,7 +13,7 @@ type SimpleWebHttp struct {
 	handler *HandlerFunc
 	host    string
 	logger  logger.ILogger
-	started bool
+	state   *StateMachine
 }
 
 // NewSimpleWebHttp...
@@ -22,6 +22,7 @@ func (manager *Manager) NewSimpleWebHttp(host string) IWeb {
 		server: &http.Server{Addr: host},
 		host:   host,
 		logger: manager.logger,
+		state:  NewStateMachine(),
 	}
 }
 
@@ -54,58 +55,35 @@ func (w *SimpleWebHttp) AddFilter(pattern string, position string, middleware Mi
 }
 
 // Start ...
-func (w *SimpleWebHttp) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if w.started {
+func (w *SimpleWebHttp) Start(ctx context.Context) error {
+	if !w.state.start() {
 		return nil
 	}
 
-	go http.ListenAndServe(w.host, nil)
-
-	w.started = true
+	go w.server.ListenAndServe()
+	w.state.started(nil)
 
 	return nil
 }
 
 // Stop ...
-func (w *SimpleWebHttp) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !w.started {
+func (w *SimpleWebHttp) Stop(ctx context.Context) (err error) {
+	if !w.state.stop() {
 		return nil
 	}
+	defer func() { w.state.stopped(err) }()
 
-	if err := w.server.Close(); err != nil {
-		return err
-	}
-
-	w.started = false
-
-	return nil
+	return w.server.Shutdown(ctx)
 }
 
 // Started ...
 func (w *SimpleWebHttp) Started() bool {
-	return w.started
+	return w.state.Started()
+}
+
+// HealthCheck ...
+func (w *SimpleWebHttp) HealthCheck(ctx context.Context) error {
+	return NewTCPHealthCheck(w.host).HealthCheck(ctx)
 }
 
 // GetClient ...
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/joaosoft/logger"
@@ -16,38 +18,48 @@ type SimpleWorkList struct {
 	list                            IList
 	workers                         []*Worker
 	logger                          logger.ILogger
-	started                         bool
+	mux                             sync.Mutex
+	state                           *StateMachine
 }
 
-// NewSimpleWorkList ...
-func (manager *Manager) NewSimpleWorkList(config *WorkListConfig, handler WorkHandler, workRecoverHandler WorkRecoverHandler, workRecoverWastedRetriesHandler WorkRecoverWastedRetriesHandler) IWorkList {
+// NewSimpleWorkList creates the work list with the list of the storage of the config, it returns an error when
+// the list can't be created (ex: the log can't be opened, or the redis or the database of the config wasn't added)
+func (manager *Manager) NewSimpleWorkList(config *WorkListConfig, handler WorkHandler, workRecoverHandler WorkRecoverHandler, workRecoverWastedRetriesHandler WorkRecoverWastedRetriesHandler) (IWorkList, error) {
+	list, err := manager.newWorkListStorage(config.Name, config.Mode, config.MaxRetries, &config.WorkListStorageConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.newSimpleWorkList(config, list, handler, workRecoverHandler, workRecoverWastedRetriesHandler), nil
+}
+
+// newSimpleWorkList ...
+func (manager *Manager) newSimpleWorkList(config *WorkListConfig, list IList, handler WorkHandler, workRecoverHandler WorkRecoverHandler, workRecoverWastedRetriesHandler WorkRecoverWastedRetriesHandler) IWorkList {
 	return &SimpleWorkList{
 		name:                            config.Name,
-		list:                            manager.NewQueue(WithMode(config.Mode)),
+		list:                            list,
 		config:                          config,
 		handler:                         handler,
 		workRecoverHandler:              workRecoverHandler,
 		workRecoverWastedRetriesHandler: workRecoverWastedRetriesHandler,
 		logger:                          manager.logger,
+		state:                           NewStateMachine(),
 	}
 }
 
 // Start ...
-func (s *SimpleWorkList) Start(waitGroup ...*sync.WaitGroup) (err error) {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (s *SimpleWorkList) Start(ctx context.Context) (err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
 
-	if s.started {
+	if !s.state.start() {
 		return nil
 	}
+	defer func() { s.state.started(err) }()
+
+	if err = openList(s.list); err != nil {
+		return err
+	}
 
 	var workers []*Worker
 	for i := 1; i <= s.config.MaxWorkers; i++ {
@@ -70,48 +82,103 @@ func (s *SimpleWorkList) Start(waitGroup ...*sync.WaitGroup) (err error) {
 	}
 
 	s.workers = workers
-	s.started = true
-
 	return nil
 }
 
-// Stop ...
-func (s *SimpleWorkList) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+// Stop stops the workers, waiting for the works being done without holding the lock
+func (s *SimpleWorkList) Stop(ctx context.Context) (err error) {
+	s.mux.Lock()
+	if !s.state.stop() {
+		s.mux.Unlock()
+		return nil
+	}
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+	workers := s.workers
+	s.workers = nil
+	s.mux.Unlock()
+	defer func() { s.state.stopped(err) }()
+
+	stopped := make(chan bool)
+	go func() {
+		for _, worker := range workers {
+			s.logger.Infof("stopping worker [ %d: %s ]", worker.id, worker.name)
+			if err := worker.Stop(); err != nil {
+				s.logger.Errorf("error stopping worker [ %d: %s ]: %s", worker.id, worker.name, err)
+			}
+		}
+
+		// the list is closed when the workers are stopped, even after the timeout of the stop
+		closeList(s.list)
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 
-	defer wg.Done()
+	return nil
+}
+
+// Started ...
+func (s *SimpleWorkList) Started() bool {
+	return s.state.Started()
+}
 
-	if !s.started {
+// SetMaxWorkers changes the number of workers, starting or stopping workers when the work list is started
+func (s *SimpleWorkList) SetMaxWorkers(maxWorkers int) error {
+	if maxWorkers < 1 {
+		return fmt.Errorf("invalid max workers [ name: %s, max workers: %d ]", s.name, maxWorkers)
+	}
+
+	s.mux.Lock()
+	s.config.MaxWorkers = maxWorkers
+	if !s.state.Started() {
+		s.mux.Unlock()
 		return nil
 	}
 
-	for _, worker := range s.workers {
+	for len(s.workers) < maxWorkers {
+		worker := NewWorker(len(s.workers)+1, s.config, s.handler, s.list, s.workRecoverHandler, s.workRecoverWastedRetriesHandler, s.logger)
+		s.logger.Infof("starting worker [ %d ]", worker.id)
+
+		if err := worker.Start(); err != nil {
+			s.mux.Unlock()
+			s.logger.Errorf("error starting worker [ %d: %s ]: %s", worker.id, worker.name, err)
+			return err
+		}
+		s.workers = append(s.workers, worker)
+	}
+
+	// the workers removed are stopped without holding the lock, while they finish their works
+	var stopping []*Worker
+	for len(s.workers) > maxWorkers {
+		stopping = append(stopping, s.workers[len(s.workers)-1])
+		s.workers = s.workers[:len(s.workers)-1]
+	}
+	s.mux.Unlock()
+
+	for _, worker := range stopping {
 		s.logger.Infof("stopping worker [ %d: %s ]", worker.id, worker.name)
+
 		if err := worker.Stop(); err != nil {
 			s.logger.Errorf("error stopping worker [ %d: %s ]: %s", worker.id, worker.name, err)
+			return err
 		}
 	}
 
-	s.started = false
-
 	return nil
 }
 
-// Started ...
-func (s *SimpleWorkList) Started() bool {
-	return s.started
-}
-
-// AddWork ...
-func (s *SimpleWorkList) AddWork(id string, data interface{}) {
+// AddWork adds the work to the list, returning the error when it can't be added (ex: the list was closed by a stop)
+func (s *SimpleWorkList) AddWork(id string, data interface{}) error {
 	s.logger.Infof("adding work to the list [ name: %s ]", s.name)
 	work := NewWork(id, data, s.logger)
-	s.list.Add(id, work)
+	if err := s.list.Add(id, work); err != nil {
+		s.logger.Errorf("error adding work to the list [ name: %s, id: %s ]: %s", s.name, id, err)
+		return err
+	}
+
+	return nil
 }
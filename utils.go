@@ -2,12 +2,9 @@ package manager
 
 import (
 	"bufio"
-	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
-	"path/filepath"
-
-	"gopkg.in/yaml.v2"
 )
 
 func GetEnv() string {
@@ -28,13 +25,20 @@ func Exists(file string) bool {
 	return true
 }
 
-func ReadFile(fileName string, obj interface{}) ([]byte, error) {
-	var err error
-
+// resolveFile returns the file on the path of the application when it doesn't exist
+func resolveFile(fileName string) string {
 	if !Exists(fileName) {
 		fileName = global[path_key].(string) + fileName
 	}
 
+	return fileName
+}
+
+func ReadFile(fileName string, obj interface{}) ([]byte, error) {
+	var err error
+
+	fileName = resolveFile(fileName)
+
 	file, err := os.Open(fileName)
 	if err != nil {
 		return nil, err
@@ -46,15 +50,8 @@ func ReadFile(fileName string, obj interface{}) ([]byte, error) {
 	}
 
 	if obj != nil {
-		switch filepath.Ext(fileName) {
-		case ".json":
-			if err := json.Unmarshal(data, obj); err != nil {
-				return nil, err
-			}
-		case ".yaml":
-			if err := yaml.Unmarshal(data, obj); err != nil {
-				return nil, err
-			}
+		if err := decodeConfig(GetConfigFormat(fileName), data, obj); err != nil {
+			return nil, err
 		}
 	}
 
@@ -64,9 +61,7 @@ func ReadFile(fileName string, obj interface{}) ([]byte, error) {
 func ReadFileLines(fileName string) ([]string, error) {
 	lines := make([]string, 0)
 
-	if !Exists(fileName) {
-		fileName = global[path_key].(string) + fileName
-	}
+	fileName = resolveFile(fileName)
 
 	file, err := os.Open(fileName)
 	if err != nil {
@@ -87,14 +82,42 @@ func ReadFileLines(fileName string) ([]string, error) {
 }
 
 func WriteFile(fileName string, obj interface{}) error {
-	if !Exists(fileName) {
-		fileName = global[path_key].(string) + fileName
+	fileName = resolveFile(fileName)
+
+	data, err := encodeConfig(GetConfigFormat(fileName), obj)
+	if err != nil {
+		return err
 	}
 
-	jsonBytes, _ := json.MarshalIndent(obj, "", "    ")
-	if err := ioutil.WriteFile(fileName, jsonBytes, 0644); err != nil {
+	if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// normalize converts the maps decoded from yaml to maps with string keys, so that they can be encoded as json
+func normalize(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[fmt.Sprint(key)] = normalize(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[key] = normalize(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			result[i] = normalize(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
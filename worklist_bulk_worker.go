@@ -28,9 +28,10 @@ type BulkWorker struct {
 	maxRetries                  int
 	sleepTime                   time.Duration
 	quit                        chan bool
+	done                        chan bool
 	mux                         *sync.Mutex
 	logger                      logger.ILogger
-	started                     bool
+	state                       *StateMachine
 }
 
 // NewBulkWorker ...
@@ -45,9 +46,9 @@ func NewBulkWorker(id int, config *BulkWorkListConfig, handler BulkWorkHandler,
 		recoverHandler:              bulkWorkRecoverHandler,
 		recoverWastedRetriesHandler: bulkWorkRecoverOneHandler,
 		list:                        list,
-		quit:                        make(chan bool),
 		mux:                         &sync.Mutex{},
 		logger:                      logger,
+		state:                       NewStateMachine(),
 	}
 
 	return bulkWorker
@@ -55,43 +56,68 @@ func NewBulkWorker(id int, config *BulkWorkListConfig, handler BulkWorkHandler,
 
 // Start ...
 func (bulkWorker *BulkWorker) Start() error {
-	go func() error {
+	bulkWorker.mux.Lock()
+	defer bulkWorker.mux.Unlock()
+
+	if !bulkWorker.state.start() {
+		return nil
+	}
+
+	quit := make(chan bool)
+	done := make(chan bool)
+	bulkWorker.quit = quit
+	bulkWorker.done = done
+
+	go func() {
+		defer close(done)
+
 		for {
 			select {
-			case <-bulkWorker.quit:
+			case <-quit:
 				logger.Debugf("worker quited [name: %s, list size: %d ]", bulkWorker.name, bulkWorker.list.Size())
 
-				return nil
+				return
 			default:
-				if bulkWorker.list.Size() > 0 {
+				if !bulkWorker.list.IsEmpty() {
 					logger.Debugf("worker starting [ name: %d, queue size: %d]", bulkWorker.name, bulkWorker.list.Size())
 					bulkWorker.execute()
 					logger.Debugf("worker finished [ name: %s, queue size: %d]", bulkWorker.name, bulkWorker.list.Size())
 				} else {
 					logger.Debugf("worker waiting for work to do... [ id: %d, name: %s ]", bulkWorker.id, bulkWorker.name)
-					<-time.After(bulkWorker.sleepTime)
+					select {
+					case <-quit:
+						logger.Debugf("worker quited [name: %s, list size: %d ]", bulkWorker.name, bulkWorker.list.Size())
+
+						return
+					case <-time.After(bulkWorker.sleepTime):
+					}
 				}
 			}
 		}
 	}()
 
-	bulkWorker.started = true
+	bulkWorker.state.started(nil)
 
 	return nil
 }
 
-// Stop ...
+// Stop signals the worker to quit and waits for the work being done, without holding the lock while waiting
 func (bulkWorker *BulkWorker) Stop() error {
-
 	bulkWorker.mux.Lock()
-	defer bulkWorker.mux.Unlock()
+	if !bulkWorker.state.stop() {
+		bulkWorker.mux.Unlock()
+		return nil
+	}
 
-	if bulkWorker.list.Size() > 0 {
+	if !bulkWorker.list.IsEmpty() {
 		logger.Infof("stopping worker with tasks in the list [ list size: %d ]", bulkWorker.list.Size())
 	}
-	bulkWorker.quit <- true
+	close(bulkWorker.quit)
+	done := bulkWorker.done
+	bulkWorker.mux.Unlock()
 
-	bulkWorker.started = false
+	<-done
+	bulkWorker.state.stopped(nil)
 
 	return nil
 }
@@ -116,12 +142,15 @@ func (bulkWorker *BulkWorker) execute() error {
 
 	var works []*Work
 	for i := 0; i < bulkWorker.maxWorks; i++ {
-		if tmp := bulkWorker.list.Remove(); tmp != nil {
-			if tmp == nil {
-				break
-			}
-			works = append(works, tmp.(*Work))
+		tmp := bulkWorker.list.Remove()
+		if tmp == nil {
+			break
 		}
+		works = append(works, tmp.(*Work))
+	}
+
+	if len(works) == 0 {
+		return nil
 	}
 
 	if err := bulkWorker.handler(works); err != nil {
@@ -139,10 +168,18 @@ func (bulkWorker *BulkWorker) execute() error {
 					}
 				}
 				logger.Errorf("work discarded of the queue [ retries: %d, error: %s ]", work.retries, err)
+				ackWorks(bulkWorker.list, work.Id)
 			}
 		}
 
 		return nil
 	}
+
+	ids := make([]string, len(works))
+	for i, work := range works {
+		ids[i] = work.Id
+	}
+	ackWorks(bulkWorker.list, ids...)
+
 	return nil
 }
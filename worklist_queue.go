@@ -6,6 +6,7 @@ import (
 	"github.com/joaosoft/logger"
 	"github.com/labstack/gommon/log"
 	"sync"
+	"time"
 )
 
 // Mode ...
@@ -34,19 +35,73 @@ type Queue struct {
 	maxSize int
 	mux     *sync.Mutex
 	ids     map[string]*Node
-	logger logger.ILogger
+	logger  logger.ILogger
+
+	persistence   string
+	fsync         FsyncPolicy
+	fsyncInterval time.Duration
+	compaction    int
+	decoder       QueueDecoder
+	log           *queueLog
+	logErr        error
+}
+
+// NewQueue creates a queue in memory or, with the persistence option, a queue with a write ahead log
+// that restores the works that weren't acknowledged, returning the error opening or replaying the log
+func (manager *Manager) NewQueue(options ...QueueOption) (IList, error) {
+	queue, err := manager.newQueue(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return queue, nil
 }
 
-// NewQueue ...
-func (manager *Manager) NewQueue(options ...QueueOption) IList {
+// newQueue creates the queue, returning the error opening its log
+func (manager *Manager) newQueue(options ...QueueOption) (*Queue, error) {
 	queue := &Queue{
-		ids: make(map[string]*Node),
-		mux: &sync.Mutex{},
-		logger: manager.logger,
+		ids:           make(map[string]*Node),
+		mux:           &sync.Mutex{},
+		logger:        manager.logger,
+		fsyncInterval: time.Second,
+		compaction:    defaultQueueCompaction,
+		decoder:       decodeQueueData,
 	}
 	queue.Reconfigure(options...)
 
-	return queue
+	if queue.persistence != "" {
+		if err := queue.restore(); err != nil {
+			// the works can't be added without the log, so that they aren't lost
+			queue.logErr = fmt.Errorf("error opening the queue log [ dir: %s ]: %w", queue.persistence, err)
+			return queue, queue.logErr
+		}
+	}
+
+	return queue, nil
+}
+
+// restore replays the log, adding the works that weren't acknowledged
+func (queue *Queue) restore() error {
+	log, records, err := openQueueLog(queue.persistence, queue.fsync, queue.fsyncInterval, queue.compaction, queue.logger)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		data, err := record.restore(queue.decoder, queue.logger)
+		if err != nil {
+			log.close()
+			return err
+		}
+		queue.push(record.ID, data)
+	}
+	queue.log = log
+
+	if len(records) > 0 {
+		queue.logger.Infof("restored works from the queue log [ dir: %s, works: %d ]", queue.persistence, len(records))
+	}
+
+	return nil
 }
 
 // Add ...
@@ -58,6 +113,22 @@ func (queue *Queue) Add(id string, data interface{}) error {
 		return fmt.Errorf("the queue is full with [ size: %d ]", queue.size)
 	}
 
+	if queue.persistence != "" {
+		if queue.log == nil {
+			return queue.logErr
+		}
+
+		if err := queue.log.add(id, data); err != nil {
+			return err
+		}
+	}
+
+	queue.push(id, data)
+	return nil
+}
+
+// push adds the node to the start of the queue
+func (queue *Queue) push(id string, data interface{}) {
 	nodeToAdd := &Node{id: id, data: data}
 	if queue.size == 0 {
 		queue.start = nodeToAdd
@@ -69,7 +140,6 @@ func (queue *Queue) Add(id string, data interface{}) error {
 	}
 	queue.ids[id] = nodeToAdd
 	queue.size++
-	return nil
 }
 
 // Remove ...
@@ -111,6 +181,12 @@ func (queue *Queue) Remove(ids ...string) interface{} {
 			return nil
 		}
 	} else {
+		if queue.log != nil {
+			if err := queue.log.ack(ids...); err != nil {
+				queue.logger.Errorf("error removing the works from the queue log [ dir: %s ]: %s", queue.persistence, err)
+			}
+		}
+
 		var nodesRemoved []interface{}
 		for _, id := range ids {
 			nodeToRemove = queue.ids[id]
@@ -121,6 +197,56 @@ func (queue *Queue) Remove(ids ...string) interface{} {
 		}
 		return nodesRemoved
 	}
+}
+
+// Ack acknowledges the works removed from the queue, that are restored from the log until then
+func (queue *Queue) Ack(ids ...string) error {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+
+	if queue.log == nil {
+		return nil
+	}
+
+	return queue.log.ack(ids...)
+}
+
+// Close closes the log of the queue
+func (queue *Queue) Close() error {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+
+	if queue.log == nil {
+		return nil
+	}
+
+	err := queue.log.close()
+	queue.log = nil
+	queue.logErr = fmt.Errorf("the queue log is closed [ dir: %s ]", queue.persistence)
+
+	return err
+}
+
+// open reopens the log of the queue after it was closed, restoring the works that weren't acknowledged
+func (queue *Queue) open() error {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+
+	if queue.persistence == "" || queue.log != nil {
+		return nil
+	}
+
+	// the works removed and not acknowledged are only on the log, so the queue is restored from it
+	queue.start = nil
+	queue.end = nil
+	queue.size = 0
+	queue.ids = make(map[string]*Node)
+
+	if err := queue.restore(); err != nil {
+		queue.logErr = fmt.Errorf("error opening the queue log [ dir: %s ]: %w", queue.persistence, err)
+		return queue.logErr
+	}
+	queue.logErr = nil
 
 	return nil
 }
@@ -142,17 +268,19 @@ func (queue *Queue) IsEmpty() bool {
 // Dump ...
 func (queue *Queue) Dump() string {
 	type queuePrint struct {
-		Size    int              `json:"size"`
-		Mode    Mode             `json:"mode"`
-		MaxSize int              `json:"max_size"`
-		Ids     map[string]*Node `json:"ids"`
+		Size        int              `json:"size"`
+		Mode        Mode             `json:"mode"`
+		MaxSize     int              `json:"max_size"`
+		Ids         map[string]*Node `json:"ids"`
+		Persistence string           `json:"persistence,omitempty"`
 	}
 
 	print := queuePrint{
-		Size:    queue.size,
-		Mode:    queue.mode,
-		MaxSize: queue.maxSize,
-		Ids:     queue.ids,
+		Size:        queue.size,
+		Mode:        queue.mode,
+		MaxSize:     queue.maxSize,
+		Ids:         queue.ids,
+		Persistence: queue.persistence,
 	}
 
 	if json, err := json.Marshal(print); err != nil {
@@ -1,5 +1,7 @@
 package manager
 
+import "time"
+
 // QueueOption ...
 type QueueOption func(queue *Queue)
 
@@ -23,3 +25,40 @@ func WithMaxSize(size int) QueueOption {
 		queue.maxSize = size
 	}
 }
+
+// WithPersistence keeps the works on a write ahead log on the directory, one by queue,
+// so that the works that weren't acknowledged are restored with their retries
+func WithPersistence(dir string) QueueOption {
+	return func(queue *Queue) {
+		queue.persistence = dir
+	}
+}
+
+// WithFsync sets when the log is synced to the disk, after each record by default
+func WithFsync(policy FsyncPolicy) QueueOption {
+	return func(queue *Queue) {
+		queue.fsync = policy
+	}
+}
+
+// WithFsyncInterval syncs the log at most once by interval
+func WithFsyncInterval(interval time.Duration) QueueOption {
+	return func(queue *Queue) {
+		queue.fsync = FsyncInterval
+		queue.fsyncInterval = interval
+	}
+}
+
+// WithCompaction sets the number of records written to the log before it is compacted, 1000 by default
+func WithCompaction(records int) QueueOption {
+	return func(queue *Queue) {
+		queue.compaction = records
+	}
+}
+
+// WithDecoder sets the decoder of the data of the works restored, that is decoded as json by default
+func WithDecoder(decoder QueueDecoder) QueueOption {
+	return func(queue *Queue) {
+		queue.decoder = decoder
+	}
+}
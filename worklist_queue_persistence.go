@@ -0,0 +1,338 @@
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+const (
+	// queueLogFile is the name of the write ahead log on the persistence directory
+	queueLogFile = "queue.wal"
+	// defaultQueueCompaction is the number of records written between compactions
+	defaultQueueCompaction = 1000
+)
+
+// operations of the queue log records
+const (
+	queueLogAdd = "add"
+	queueLogAck = "ack"
+)
+
+// FsyncPolicy ...
+type FsyncPolicy int
+
+const (
+	// FsyncAlways syncs the log after each record
+	FsyncAlways FsyncPolicy = iota
+	// FsyncInterval syncs the log at most once by interval
+	FsyncInterval
+	// FsyncNever leaves the sync to the operating system
+	FsyncNever
+)
+
+// IAckList is a list that keeps the works removed until they are acknowledged, so that they can be restored
+type IAckList interface {
+	IList
+	Ack(ids ...string) error
+}
+
+// queueRecord is a record of the queue log
+type queueRecord struct {
+	Op  string `json:"op"`
+	Seq uint64 `json:"seq"`
+	listItem
+}
+
+// queueLog is an append only log of the queue, with the records of the works added and acknowledged.
+// the works removed from the queue are kept on the log until they are acknowledged
+type queueLog struct {
+	dir        string
+	file       *os.File
+	writer     *bufio.Writer
+	fsync      FsyncPolicy
+	interval   time.Duration
+	compaction int
+	written    int
+	seq        uint64
+	pending    map[string]*queueRecord
+	syncTimer  *time.Timer
+	syncMux    sync.Mutex
+	logger     logger.ILogger
+}
+
+// openQueueLog replays the log of the directory, returning the pending records by their order
+func openQueueLog(dir string, fsync FsyncPolicy, interval time.Duration, compaction int, logger logger.ILogger) (*queueLog, []*queueRecord, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, err
+	}
+
+	log := &queueLog{
+		dir:        dir,
+		fsync:      fsync,
+		interval:   interval,
+		compaction: compaction,
+		pending:    make(map[string]*queueRecord),
+		logger:     logger,
+	}
+
+	if err := log.replay(); err != nil {
+		return nil, nil, err
+	}
+
+	// the log starts compacted, without the acknowledged works and an interrupted last record
+	if err := log.compact(); err != nil {
+		return nil, nil, err
+	}
+
+	return log, log.sorted(), nil
+}
+
+// path ...
+func (log *queueLog) path() string {
+	return filepath.Join(log.dir, queueLogFile)
+}
+
+// replay reads the records of the log, ignoring the last one when it was interrupted
+func (log *queueLog) replay() error {
+	file, err := os.Open(log.path())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if len(data) > 0 {
+			record := &queueRecord{}
+			if errDecode := json.Unmarshal(data, record); errDecode != nil {
+				if err == io.EOF {
+					log.logger.Warnf("ignoring the interrupted last record of the queue log [ file: %s, line: %d ]", log.path(), line)
+					return nil
+				}
+				return fmt.Errorf("invalid queue log record [ file: %s, line: %d ]: %w", log.path(), line, errDecode)
+			}
+			log.apply(record)
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// apply applies the record to the pending works
+func (log *queueLog) apply(record *queueRecord) {
+	if record.Seq > log.seq {
+		log.seq = record.Seq
+	}
+
+	switch record.Op {
+	case queueLogAdd:
+		log.pending[record.ID] = record
+	case queueLogAck:
+		delete(log.pending, record.ID)
+	}
+}
+
+// sorted returns the pending records by the order they were added
+func (log *queueLog) sorted() []*queueRecord {
+	records := make([]*queueRecord, 0, len(log.pending))
+	for _, record := range log.pending {
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Seq < records[j].Seq
+	})
+
+	return records
+}
+
+// add writes the record of an added work
+func (log *queueLog) add(id string, data interface{}) error {
+	item, err := newListItem(id, data)
+	if err != nil {
+		return err
+	}
+
+	return log.write(&queueRecord{Op: queueLogAdd, listItem: *item})
+}
+
+// ack writes the records of the acknowledged works
+func (log *queueLog) ack(ids ...string) error {
+	for _, id := range ids {
+		if _, exists := log.pending[id]; !exists {
+			continue
+		}
+
+		if err := log.write(&queueRecord{Op: queueLogAck, listItem: listItem{ID: id}}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// write appends the record to the log, compacting the log when it has enough records
+func (log *queueLog) write(record *queueRecord) error {
+	log.seq++
+	record.Seq = log.seq
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if _, err = log.writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	if err = log.flush(); err != nil {
+		return err
+	}
+	log.apply(record)
+
+	log.written++
+	if log.compaction > 0 && log.written >= log.compaction && log.written > 2*len(log.pending) {
+		return log.compact()
+	}
+
+	return nil
+}
+
+// flush writes the buffered records to the file, syncing it by the fsync policy
+func (log *queueLog) flush() error {
+	if err := log.writer.Flush(); err != nil {
+		return err
+	}
+
+	switch log.fsync {
+	case FsyncAlways:
+		return log.file.Sync()
+	case FsyncInterval:
+		log.syncMux.Lock()
+		defer log.syncMux.Unlock()
+
+		if log.syncTimer == nil {
+			log.syncTimer = time.AfterFunc(log.interval, func() {
+				log.syncMux.Lock()
+				defer log.syncMux.Unlock()
+
+				log.syncTimer = nil
+				if err := log.file.Sync(); err != nil && !errors.Is(err, os.ErrClosed) {
+					log.logger.Errorf("error syncing the queue log [ file: %s ]: %s", log.path(), err)
+				}
+			})
+		}
+	}
+
+	return nil
+}
+
+// compact rewrites the log with the pending works only
+func (log *queueLog) compact() error {
+	tmp := log.path() + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, record := range log.sorted() {
+		data, err := json.Marshal(record)
+		if err != nil {
+			file.Close()
+			return err
+		}
+
+		if _, err = writer.Write(append(data, '\n')); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, log.path()); err != nil {
+		return err
+	}
+	syncDir(log.dir)
+
+	appended, err := os.OpenFile(log.path(), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	// the file is replaced while the interval sync isn't running
+	log.syncMux.Lock()
+	if log.file != nil {
+		log.file.Close()
+	}
+	log.file = appended
+	log.syncMux.Unlock()
+
+	log.writer = bufio.NewWriter(log.file)
+	log.written = 0
+
+	return nil
+}
+
+// close syncs and closes the log
+func (log *queueLog) close() error {
+	log.syncMux.Lock()
+	defer log.syncMux.Unlock()
+
+	if log.syncTimer != nil {
+		log.syncTimer.Stop()
+		log.syncTimer = nil
+	}
+
+	if err := log.writer.Flush(); err != nil {
+		return err
+	}
+
+	if err := log.file.Sync(); err != nil {
+		return err
+	}
+
+	return log.file.Close()
+}
+
+// syncDir syncs the directory, so that the renamed files are kept on failures
+func syncDir(dir string) {
+	if file, err := os.Open(dir); err == nil {
+		file.Sync()
+		file.Close()
+	}
+}
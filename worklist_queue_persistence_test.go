@@ -0,0 +1,272 @@
+package manager
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// drainQueue removes all the works of the queue, returning their ids by the order they were removed
+func drainQueue(t *testing.T, queue *Queue) []string {
+	t.Helper()
+
+	ids := make([]string, 0)
+	for data := queue.Remove(); data != nil; data = queue.Remove() {
+		work, ok := data.(*Work)
+		if !ok {
+			t.Fatalf("the work restored isn't a work [ type: %T ]", data)
+		}
+		ids = append(ids, work.Id)
+	}
+
+	return ids
+}
+
+// countQueueLogRecords counts the records of the log of the directory
+func countQueueLogRecords(t *testing.T, dir string) int {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join(dir, queueLogFile))
+	if err != nil {
+		t.Fatalf("error opening the queue log: %s", err)
+	}
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		count++
+	}
+
+	return count
+}
+
+func TestQueueLogReplay(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     Mode
+		add      []string
+		remove   int
+		ack      int
+		removeID string
+		expected []string
+	}{
+		{name: "pending works are restored", add: []string{"a", "b", "c"}, expected: []string{"a", "b", "c"}},
+		{name: "acknowledged works aren't restored", add: []string{"a", "b", "c"}, remove: 2, ack: 2, expected: []string{"c"}},
+		{name: "removed works not acknowledged are restored", add: []string{"a", "b", "c"}, remove: 2, ack: 1, expected: []string{"b", "c"}},
+		{name: "works removed by id aren't restored", add: []string{"a", "b", "c"}, removeID: "b", expected: []string{"a", "c"}},
+		{name: "works restored on lifo", mode: LIFO, add: []string{"a", "b", "c"}, remove: 1, ack: 1, expected: []string{"b", "a"}},
+	}
+
+	m := NewManager()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			queue, err := m.newQueue(WithPersistence(dir), WithMode(test.mode))
+			if err != nil {
+				t.Fatalf("error creating the queue: %s", err)
+			}
+
+			for _, id := range test.add {
+				if err := queue.Add(id, NewWork(id, "data-"+id, m.logger)); err != nil {
+					t.Fatalf("error adding the work %s: %s", id, err)
+				}
+			}
+
+			removed := make([]string, 0)
+			for i := 0; i < test.remove; i++ {
+				removed = append(removed, queue.Remove().(*Work).Id)
+			}
+
+			if err := queue.Ack(removed[:test.ack]...); err != nil {
+				t.Fatalf("error acknowledging the works: %s", err)
+			}
+
+			if test.removeID != "" {
+				queue.Remove(test.removeID)
+			}
+
+			if err := queue.Close(); err != nil {
+				t.Fatalf("error closing the queue: %s", err)
+			}
+
+			restored, err := m.newQueue(WithPersistence(dir), WithMode(test.mode))
+			if err != nil {
+				t.Fatalf("error restoring the queue: %s", err)
+			}
+			defer restored.Close()
+
+			if ids := drainQueue(t, restored); !reflect.DeepEqual(ids, test.expected) {
+				t.Errorf("restored the works %v, expected %v", ids, test.expected)
+			}
+		})
+	}
+}
+
+func TestQueueLogRestoresRetries(t *testing.T) {
+	m := NewManager()
+	dir := t.TempDir()
+
+	queue, err := m.newQueue(WithPersistence(dir))
+	if err != nil {
+		t.Fatalf("error creating the queue: %s", err)
+	}
+
+	work := NewWork("a", map[string]interface{}{"order": "1"}, m.logger)
+	work.retries = 2
+	if err := queue.Add(work.Id, work); err != nil {
+		t.Fatalf("error adding the work: %s", err)
+	}
+	queue.Close()
+
+	restored, err := m.newQueue(WithPersistence(dir))
+	if err != nil {
+		t.Fatalf("error restoring the queue: %s", err)
+	}
+	defer restored.Close()
+
+	restoredWork := restored.Remove().(*Work)
+	if restoredWork.retries != 2 || !reflect.DeepEqual(restoredWork.Data, work.Data) {
+		t.Errorf("restored the work with the retries %d and the data %v, expected %d and %v", restoredWork.retries, restoredWork.Data, work.retries, work.Data)
+	}
+}
+
+func TestQueueLogDamagedRecords(t *testing.T) {
+	tests := []struct {
+		name     string
+		append   string
+		valid    bool
+		expected []string
+	}{
+		{name: "interrupted last record is ignored", append: `{"op":"add","seq":3,"id":"c","da`, valid: true, expected: []string{"a", "b"}},
+		{name: "invalid record is an error", append: "invalid\n" + `{"op":"ack","seq":3,"id":"a"}` + "\n", valid: false},
+	}
+
+	m := NewManager()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			queue, err := m.newQueue(WithPersistence(dir))
+			if err != nil {
+				t.Fatalf("error creating the queue: %s", err)
+			}
+			queue.Add("a", NewWork("a", "data-a", m.logger))
+			queue.Add("b", NewWork("b", "data-b", m.logger))
+			queue.Close()
+
+			file, err := os.OpenFile(filepath.Join(dir, queueLogFile), os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				t.Fatalf("error opening the queue log: %s", err)
+			}
+			file.WriteString(test.append)
+			file.Close()
+
+			restored, err := m.newQueue(WithPersistence(dir))
+			if !test.valid {
+				if err == nil {
+					t.Fatal("expected an error restoring the queue")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error restoring the queue: %s", err)
+			}
+			defer restored.Close()
+
+			if ids := drainQueue(t, restored); !reflect.DeepEqual(ids, test.expected) {
+				t.Errorf("restored the works %v, expected %v", ids, test.expected)
+			}
+		})
+	}
+}
+
+func TestQueueLogCompaction(t *testing.T) {
+	tests := []struct {
+		name       string
+		compaction int
+		works      int
+		pending    int
+		maxRecords int
+	}{
+		{name: "compacted after the records", compaction: 10, works: 100, pending: 0, maxRecords: 10},
+		{name: "compacted keeping the pending works", compaction: 10, works: 100, pending: 3, maxRecords: 13},
+		{name: "not compacted without compaction", compaction: 0, works: 20, pending: 0, maxRecords: 40},
+	}
+
+	m := NewManager()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			queue, err := m.newQueue(WithPersistence(dir), WithCompaction(test.compaction))
+			if err != nil {
+				t.Fatalf("error creating the queue: %s", err)
+			}
+			defer queue.Close()
+
+			for i := 0; i < test.works; i++ {
+				id := "done-" + strconv.Itoa(i)
+				if err := queue.Add(id, NewWork(id, "data", m.logger)); err != nil {
+					t.Fatalf("error adding the work %s: %s", id, err)
+				}
+				if err := queue.Ack(queue.Remove().(*Work).Id); err != nil {
+					t.Fatalf("error acknowledging the work %s: %s", id, err)
+				}
+			}
+
+			for i := 0; i < test.pending; i++ {
+				id := "pending-" + strconv.Itoa(i)
+				if err := queue.Add(id, NewWork(id, "data", m.logger)); err != nil {
+					t.Fatalf("error adding the work %s: %s", id, err)
+				}
+			}
+
+			if records := countQueueLogRecords(t, dir); records > test.maxRecords {
+				t.Errorf("the log has %d records, expected at most %d", records, test.maxRecords)
+			}
+
+			if queue.Size() != test.pending {
+				t.Errorf("the queue has %d works, expected %d", queue.Size(), test.pending)
+			}
+		})
+	}
+}
+
+func TestQueueLogReopen(t *testing.T) {
+	m := NewManager()
+	dir := t.TempDir()
+
+	queue, err := m.newQueue(WithPersistence(dir))
+	if err != nil {
+		t.Fatalf("error creating the queue: %s", err)
+	}
+	queue.Add("a", NewWork("a", "data-a", m.logger))
+	queue.Remove()
+
+	if err := queue.Close(); err != nil {
+		t.Fatalf("error closing the queue: %s", err)
+	}
+
+	if err := queue.Add("b", NewWork("b", "data-b", m.logger)); err == nil {
+		t.Error("expected an error adding a work to the closed queue")
+	}
+
+	if err := queue.open(); err != nil {
+		t.Fatalf("error opening the queue: %s", err)
+	}
+	defer queue.Close()
+
+	if err := queue.Add("b", NewWork("b", "data-b", m.logger)); err != nil {
+		t.Fatalf("error adding the work to the opened queue: %s", err)
+	}
+
+	if ids := drainQueue(t, queue); !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Errorf("the opened queue has the works %v, expected [a b]", ids)
+	}
+}
@@ -0,0 +1,410 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+// RedisListOption ...
+type RedisListOption func(list *RedisList)
+
+// Reconfigure ...
+func (list *RedisList) Reconfigure(options ...RedisListOption) {
+	for _, option := range options {
+		option(list)
+	}
+}
+
+// WithRedisMode ...
+func WithRedisMode(mode Mode) RedisListOption {
+	return func(list *RedisList) {
+		list.mode = mode
+	}
+}
+
+// WithVisibilityTimeout sets the time a removed work can take until it is acknowledged,
+// before it is added again for other workers, 1 minute by default
+func WithVisibilityTimeout(timeout time.Duration) RedisListOption {
+	return func(list *RedisList) {
+		list.visibilityTimeout = timeout
+	}
+}
+
+// WithReapInterval sets the interval between the searches of the works abandoned, the visibility timeout by default
+func WithReapInterval(interval time.Duration) RedisListOption {
+	return func(list *RedisList) {
+		list.reapInterval = interval
+	}
+}
+
+// WithRedisMaxAttempts sets the times a work can be removed, the works abandoned more times (ex: the works of workers
+// that stopped while doing them) are discarded when they are reaped, without limit by default
+func WithRedisMaxAttempts(attempts int) RedisListOption {
+	return func(list *RedisList) {
+		list.maxAttempts = attempts
+	}
+}
+
+// WithRedisDecoder sets the decoder of the data of the works, that is decoded as json by default
+func WithRedisDecoder(decoder QueueDecoder) RedisListOption {
+	return func(list *RedisList) {
+		list.decoder = decoder
+	}
+}
+
+// RedisList is a list shared by the instances on the redis lists of its name. the works removed are moved to
+// the processing list (name:processing) with a deadline on the claims (name:claims), until they are acknowledged.
+// the works that aren't acknowledged until the deadline, from workers that stopped, are added again to the list,
+// with the abandoned attempt counted on the retries of the work
+type RedisList struct {
+	redis             IRedis
+	name              string
+	mode              Mode
+	visibilityTimeout time.Duration
+	reapInterval      time.Duration
+	maxAttempts       int
+	decoder           QueueDecoder
+	// inflight has the removed works of this instance, by id, as they are on the processing list
+	inflight map[string][]byte
+	lastReap time.Time
+	logger   logger.ILogger
+	mux      sync.Mutex
+}
+
+// NewRedisList ...
+func (manager *Manager) NewRedisList(redis IRedis, name string, options ...RedisListOption) IList {
+	list := &RedisList{
+		redis:             redis,
+		name:              name,
+		visibilityTimeout: time.Minute,
+		decoder:           decodeQueueData,
+		inflight:          make(map[string][]byte),
+		logger:            manager.logger,
+	}
+	list.Reconfigure(options...)
+
+	if list.reapInterval <= 0 {
+		list.reapInterval = list.visibilityTimeout
+	}
+
+	return list
+}
+
+// processing ...
+func (list *RedisList) processing() string {
+	return list.name + ":processing"
+}
+
+// claims ...
+func (list *RedisList) claims() string {
+	return list.name + ":claims"
+}
+
+// Add adds the work to the list, replacing the one removed by this instance with the same id (ex: a retry)
+func (list *RedisList) Add(id string, data interface{}) error {
+	item, err := newListItem(id, data)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	// the works are removed from the end of the list, that has the oldest ones on fifo mode and the newest on lifo
+	if list.mode == LIFO {
+		err = list.redis.Rpush(list.name, encoded)
+	} else {
+		err = list.redis.Lpush(list.name, encoded)
+	}
+	if err != nil {
+		return fmt.Errorf("error adding the work to the redis list [ name: %s, id: %s ]: %w", list.name, id, err)
+	}
+
+	// the work added again is only released after being added, so that it isn't lost
+	return list.release(id)
+}
+
+// Remove removes the next work or, with ids, removes the works that weren't removed yet
+func (list *RedisList) Remove(ids ...string) interface{} {
+	if len(ids) > 0 {
+		return list.removeIds(ids...)
+	}
+
+	encoded, err := list.redis.Rpoplpush(list.name, list.processing())
+	if err != nil {
+		list.logger.Errorf("error removing the work from the redis list [ name: %s ]: %s", list.name, err)
+		return nil
+	}
+	if encoded == nil {
+		return nil
+	}
+
+	deadline := time.Now().Add(list.visibilityTimeout)
+	if _, err = list.redis.Zadd(list.claims(), float64(deadline.UnixMilli()), encoded); err != nil {
+		// the work is claimed by the next reap without the deadline
+		list.logger.Errorf("error claiming the work of the redis list [ name: %s ]: %s", list.name, err)
+	}
+
+	item := &listItem{}
+	if err = json.Unmarshal(encoded, item); err != nil {
+		list.logger.Errorf("error decoding the work of the redis list, discarding it [ name: %s ]: %s", list.name, err)
+		list.discard(encoded)
+		return nil
+	}
+
+	data, err := item.restore(list.decoder, list.logger)
+	if err != nil {
+		list.logger.Errorf("error restoring the work of the redis list, discarding it [ name: %s ]: %s", list.name, err)
+		list.discard(encoded)
+		return nil
+	}
+
+	list.mux.Lock()
+	list.inflight[item.ID] = encoded
+	list.mux.Unlock()
+
+	return data
+}
+
+// removeIds removes the works with the ids from the list
+func (list *RedisList) removeIds(ids ...string) interface{} {
+	items, err := list.redis.Lrange(list.name, 0, -1)
+	if err != nil {
+		list.logger.Errorf("error reading the redis list [ name: %s ]: %s", list.name, err)
+		return nil
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var removed []interface{}
+	for _, encoded := range items {
+		item := &listItem{}
+		if err = json.Unmarshal(encoded, item); err != nil || !wanted[item.ID] {
+			continue
+		}
+
+		if count, err := list.redis.Lrem(list.name, encoded, 1); err != nil || count == 0 {
+			continue
+		}
+
+		if data, err := item.restore(list.decoder, list.logger); err == nil {
+			removed = append(removed, data)
+		}
+	}
+
+	return removed
+}
+
+// Ack acknowledges the works removed by this instance, removing them from the processing list
+func (list *RedisList) Ack(ids ...string) error {
+	for _, id := range ids {
+		if err := list.release(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// release removes the work removed by this instance from the processing list
+func (list *RedisList) release(id string) error {
+	list.mux.Lock()
+	encoded, exists := list.inflight[id]
+	delete(list.inflight, id)
+	list.mux.Unlock()
+
+	if !exists {
+		return nil
+	}
+
+	if err := list.discard(encoded); err != nil {
+		return fmt.Errorf("error acknowledging the work of the redis list [ name: %s, id: %s ]: %w", list.name, id, err)
+	}
+
+	return nil
+}
+
+// discard removes the work from the processing list and its claim
+func (list *RedisList) discard(encoded []byte) error {
+	if _, err := list.redis.Lrem(list.processing(), encoded, 1); err != nil {
+		return err
+	}
+
+	_, err := list.redis.Zrem(list.claims(), encoded)
+	return err
+}
+
+// Reap adds again to the list the works that weren't acknowledged until their deadline, discarding the ones
+// that exceeded the attempts, and sets the deadline of the works on the processing list without it
+func (list *RedisList) Reap() error {
+	processing, err := list.redis.Lrange(list.processing(), 0, -1)
+	if err != nil {
+		return err
+	}
+
+	claimed, err := list.redis.Zrange(list.claims(), 0, -1)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expired, err := list.redis.Zrangebyscore(list.claims(), 0, float64(now.UnixMilli()))
+	if err != nil {
+		return err
+	}
+
+	claims := make(map[string]bool, len(claimed))
+	for _, encoded := range claimed {
+		claims[string(encoded)] = true
+	}
+
+	deadlines := make(map[string]bool, len(expired))
+	for _, encoded := range expired {
+		deadlines[string(encoded)] = true
+	}
+
+	reaped := 0
+	for _, encoded := range processing {
+		switch {
+		case !claims[string(encoded)]:
+			// the worker stopped before setting the deadline
+			if _, err = list.redis.Zadd(list.claims(), float64(now.Add(list.visibilityTimeout).UnixMilli()), encoded); err != nil {
+				return err
+			}
+			claims[string(encoded)] = true
+
+		case deadlines[string(encoded)]:
+			// only the instance that removes the work from the processing list adds it again
+			count, err := list.redis.Lrem(list.processing(), encoded, 1)
+			if err != nil {
+				return err
+			}
+
+			if count > 0 {
+				if attempted, ok := list.attempt(encoded); ok {
+					if err = list.redis.Rpush(list.name, attempted); err != nil {
+						return err
+					}
+					reaped++
+				}
+			}
+
+			if _, err = list.redis.Zrem(list.claims(), encoded); err != nil {
+				return err
+			}
+			delete(deadlines, string(encoded))
+		}
+	}
+
+	// the claims of the works acknowledged without removing the claim
+	for encoded := range deadlines {
+		if _, err = list.redis.Zrem(list.claims(), []byte(encoded)); err != nil {
+			return err
+		}
+	}
+
+	if reaped > 0 {
+		list.logger.Infof("added again the works abandoned on the redis list [ name: %s, works: %d ]", list.name, reaped)
+	}
+
+	return nil
+}
+
+// attempt counts the abandoned attempt on the retries of the work, returning false when the work exceeded the attempts.
+// the works that can't be decoded are added again as they are, to be discarded when they are removed
+func (list *RedisList) attempt(encoded []byte) ([]byte, bool) {
+	item := &listItem{}
+	if err := json.Unmarshal(encoded, item); err != nil {
+		return encoded, true
+	}
+
+	item.Retries++
+	if list.maxAttempts > 0 && item.Retries >= list.maxAttempts {
+		list.logger.Errorf("work discarded of the redis list, it exceeded the attempts [ name: %s, id: %s, attempts: %d ]", list.name, item.ID, item.Retries)
+		return nil, false
+	}
+
+	attempted, err := json.Marshal(item)
+	if err != nil {
+		return encoded, true
+	}
+
+	return attempted, true
+}
+
+// reap reaps the list when the reap interval has passed
+func (list *RedisList) reap() {
+	list.mux.Lock()
+	if time.Since(list.lastReap) < list.reapInterval {
+		list.mux.Unlock()
+		return
+	}
+	list.lastReap = time.Now()
+	list.mux.Unlock()
+
+	if err := list.Reap(); err != nil {
+		list.logger.Errorf("error reaping the redis list [ name: %s ]: %s", list.name, err)
+	}
+}
+
+// Size gets the number of works waiting on the list, reaping the list when the reap interval has passed
+func (list *RedisList) Size() int {
+	list.reap()
+
+	size, err := list.redis.Llen(list.name)
+	if err != nil {
+		list.logger.Errorf("error getting the size of the redis list [ name: %s ]: %s", list.name, err)
+		return 0
+	}
+
+	return int(size)
+}
+
+// IsEmpty ...
+func (list *RedisList) IsEmpty() bool {
+	return list.Size() == 0
+}
+
+// Dump ...
+func (list *RedisList) Dump() string {
+	type redisListPrint struct {
+		Name              string        `json:"name"`
+		Mode              Mode          `json:"mode"`
+		Size              int64         `json:"size"`
+		Processing        int64         `json:"processing"`
+		VisibilityTimeout time.Duration `json:"visibility_timeout"`
+	}
+
+	print := redisListPrint{
+		Name:              list.name,
+		Mode:              list.mode,
+		VisibilityTimeout: list.visibilityTimeout,
+	}
+
+	var err error
+	if print.Size, err = list.redis.Llen(list.name); err != nil {
+		list.logger.Error(err)
+		return ""
+	}
+
+	if print.Processing, err = list.redis.Llen(list.processing()); err != nil {
+		list.logger.Error(err)
+		return ""
+	}
+
+	if json, err := json.Marshal(print); err != nil {
+		list.logger.Error(err)
+		return ""
+	} else {
+		return string(json)
+	}
+}
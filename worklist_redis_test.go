@@ -0,0 +1,223 @@
+package manager
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis keeps the lists and the sorted sets used by the redis list in memory
+type fakeRedis struct {
+	IRedis
+	lists map[string][][]byte
+	zsets map[string]map[string]float64
+	mux   sync.Mutex
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		lists: make(map[string][][]byte),
+		zsets: make(map[string]map[string]float64),
+	}
+}
+
+func (redis *fakeRedis) Lpush(key string, value []byte) error {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	redis.lists[key] = append([][]byte{value}, redis.lists[key]...)
+	return nil
+}
+
+func (redis *fakeRedis) Rpush(key string, value []byte) error {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	redis.lists[key] = append(redis.lists[key], value)
+	return nil
+}
+
+func (redis *fakeRedis) Llen(key string) (int64, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	return int64(len(redis.lists[key])), nil
+}
+
+func (redis *fakeRedis) Lrange(key string, start int64, stop int64) ([][]byte, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	return append([][]byte{}, redis.lists[key]...), nil
+}
+
+func (redis *fakeRedis) Rpoplpush(source string, destination string) ([]byte, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	list := redis.lists[source]
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	value := list[len(list)-1]
+	redis.lists[source] = list[:len(list)-1]
+	redis.lists[destination] = append([][]byte{value}, redis.lists[destination]...)
+	return value, nil
+}
+
+func (redis *fakeRedis) Lrem(key string, value []byte, count int64) (int64, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	var removed int64
+	var kept [][]byte
+	for _, item := range redis.lists[key] {
+		if removed < count && bytes.Equal(item, value) {
+			removed++
+			continue
+		}
+		kept = append(kept, item)
+	}
+	redis.lists[key] = kept
+
+	return removed, nil
+}
+
+func (redis *fakeRedis) Zadd(key string, score float64, member []byte) (bool, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	if redis.zsets[key] == nil {
+		redis.zsets[key] = make(map[string]float64)
+	}
+	_, exists := redis.zsets[key][string(member)]
+	redis.zsets[key][string(member)] = score
+
+	return !exists, nil
+}
+
+func (redis *fakeRedis) Zrem(key string, member []byte) (bool, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	_, exists := redis.zsets[key][string(member)]
+	delete(redis.zsets[key], string(member))
+
+	return exists, nil
+}
+
+func (redis *fakeRedis) Zrange(key string, start int64, stop int64) ([][]byte, error) {
+	return redis.Zrangebyscore(key, 0, float64(time.Now().Add(24*time.Hour).UnixMilli()))
+}
+
+func (redis *fakeRedis) Zrangebyscore(key string, min float64, max float64) ([][]byte, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	var members [][]byte
+	for member, score := range redis.zsets[key] {
+		if score >= min && score <= max {
+			members = append(members, []byte(member))
+		}
+	}
+
+	return members, nil
+}
+
+func TestRedisListReap(t *testing.T) {
+	tests := []struct {
+		name              string
+		visibilityTimeout time.Duration
+		ack               bool
+		unclaimed         bool
+		maxAttempts       int
+		retries           int
+		expectedList      int
+		expectedInProcess int
+		expectedClaims    int
+		expectedRetries   int
+	}{
+		{name: "abandoned works are added again", visibilityTimeout: time.Millisecond, expectedList: 1, expectedRetries: 1},
+		{name: "abandoned works keep their retries", visibilityTimeout: time.Millisecond, maxAttempts: 3, retries: 1, expectedList: 1, expectedRetries: 2},
+		{name: "abandoned works over the attempts are discarded", visibilityTimeout: time.Millisecond, maxAttempts: 2, retries: 1},
+		{name: "acknowledged works aren't added again", visibilityTimeout: time.Millisecond, ack: true},
+		{name: "works before the deadline stay claimed", visibilityTimeout: time.Hour, expectedInProcess: 1, expectedClaims: 1},
+		{name: "works without a claim get a deadline", visibilityTimeout: time.Millisecond, unclaimed: true, expectedInProcess: 1, expectedClaims: 1},
+	}
+
+	m := NewManager()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			redis := newFakeRedis()
+			list := m.NewRedisList(redis, "works", WithVisibilityTimeout(test.visibilityTimeout), WithRedisMaxAttempts(test.maxAttempts)).(*RedisList)
+
+			added := NewWork("a", "data-a", m.logger)
+			added.retries = test.retries
+			if err := list.Add("a", added); err != nil {
+				t.Fatalf("error adding the work: %s", err)
+			}
+
+			work, ok := list.Remove().(*Work)
+			if !ok || work.Id != "a" {
+				t.Fatalf("removed the work %v, expected the work a", work)
+			}
+
+			if test.ack {
+				if err := list.Ack(work.Id); err != nil {
+					t.Fatalf("error acknowledging the work: %s", err)
+				}
+			}
+
+			if test.unclaimed {
+				// the worker stopped after removing the work, before claiming it
+				redis.zsets[list.claims()] = nil
+			}
+
+			time.Sleep(5 * time.Millisecond)
+			if err := list.Reap(); err != nil {
+				t.Fatalf("error reaping the list: %s", err)
+			}
+
+			if size := len(redis.lists[list.name]); size != test.expectedList {
+				t.Errorf("the list has %d works, expected %d", size, test.expectedList)
+			}
+
+			if size := len(redis.lists[list.processing()]); size != test.expectedInProcess {
+				t.Errorf("the processing list has %d works, expected %d", size, test.expectedInProcess)
+			}
+
+			if size := len(redis.zsets[list.claims()]); size != test.expectedClaims {
+				t.Errorf("the claims have %d works, expected %d", size, test.expectedClaims)
+			}
+
+			if test.expectedList > 0 {
+				if reaped, ok := list.Remove().(*Work); !ok || reaped.retries != test.expectedRetries {
+					t.Errorf("removed the reaped work %v, expected the work a with %d retries", reaped, test.expectedRetries)
+				}
+			}
+		})
+	}
+}
+
+func TestRedisListReapedWorkIsRemovedAgain(t *testing.T) {
+	m := NewManager()
+	redis := newFakeRedis()
+	list := m.NewRedisList(redis, "works", WithVisibilityTimeout(time.Millisecond), WithReapInterval(time.Millisecond))
+
+	list.Add("a", NewWork("a", "data-a", m.logger))
+	list.Remove()
+
+	time.Sleep(5 * time.Millisecond)
+
+	// the size reaps the list after the reap interval
+	if size := list.Size(); size != 1 {
+		t.Fatalf("the list has %d works, expected 1", size)
+	}
+
+	work, ok := list.Remove().(*Work)
+	if !ok || work.Id != "a" || work.Data != "data-a" {
+		t.Errorf("removed the work %v, expected the work a", work)
+	}
+}
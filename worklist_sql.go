@@ -0,0 +1,585 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+// statuses of the works of the sql list
+const (
+	sqlListPending    = "pending"
+	sqlListProcessing = "processing"
+)
+
+// sqlListAvailable is the condition of the works available, that are pending or with their lock ended
+const sqlListAvailable = "(status = ? AND available_at <= ?) OR (status = ? AND locked_until <= ?)"
+
+// SQLListOption ...
+type SQLListOption func(list *SQLList)
+
+// Reconfigure ...
+func (list *SQLList) Reconfigure(options ...SQLListOption) {
+	for _, option := range options {
+		option(list)
+	}
+}
+
+// WithSQLMode ...
+func WithSQLMode(mode Mode) SQLListOption {
+	return func(list *SQLList) {
+		list.mode = mode
+	}
+}
+
+// WithLockTimeout sets the time a removed work is locked until it is acknowledged,
+// before it can be removed again by other workers, 1 minute by default
+func WithLockTimeout(timeout time.Duration) SQLListOption {
+	return func(list *SQLList) {
+		list.lockTimeout = timeout
+	}
+}
+
+// WithRetryDelay sets the time a work added again (ex: a retry) waits until it is available, without delay by default
+func WithRetryDelay(delay time.Duration) SQLListOption {
+	return func(list *SQLList) {
+		list.retryDelay = delay
+	}
+}
+
+// WithMaxAttempts sets the times a work can be locked, the works locked more times (ex: the works of workers that
+// stopped while doing them) are discarded when they are removed again, without limit by default
+func WithMaxAttempts(attempts int) SQLListOption {
+	return func(list *SQLList) {
+		list.maxAttempts = attempts
+	}
+}
+
+// WithSQLTimeout sets the timeout of the queries, 10 seconds by default
+func WithSQLTimeout(timeout time.Duration) SQLListOption {
+	return func(list *SQLList) {
+		list.timeout = timeout
+	}
+}
+
+// WithSQLDecoder sets the decoder of the data of the works, that is decoded as json by default
+func WithSQLDecoder(decoder QueueDecoder) SQLListOption {
+	return func(list *SQLList) {
+		list.decoder = decoder
+	}
+}
+
+// SQLList is a list with the works on a table, shared by the instances. the works are removed by locking them
+// with select for update skip locked, and are deleted when they are acknowledged. the works that aren't
+// acknowledged until their lock ends, from workers that stopped, can be removed again. the attempts of the works
+// are the times they were locked, and are restored as the retries of the works.
+// the table is created when it doesn't exist, on postgres or mysql (8.0 or newer, with parseTime=true on the datasource)
+type SQLList struct {
+	db          IDB
+	table       string
+	mode        Mode
+	lockTimeout time.Duration
+	retryDelay  time.Duration
+	maxAttempts int
+	timeout     time.Duration
+	decoder     QueueDecoder
+	// inflight has the locks of the works removed by this instance, by id
+	inflight map[string]*sqlListLock
+	migrated bool
+	logger   logger.ILogger
+	mux      sync.Mutex
+}
+
+// sqlListLock is the lock of a work removed, that is only changed while it has the same lock
+type sqlListLock struct {
+	row         int64
+	lockedUntil time.Time
+}
+
+// NewSQLList creates a list on the table, that must be a valid sql identifier
+func (manager *Manager) NewSQLList(db IDB, table string, options ...SQLListOption) (IList, error) {
+	if err := validateSQLIdentifier(table); err != nil {
+		return nil, err
+	}
+
+	list := &SQLList{
+		db:          db,
+		table:       table,
+		lockTimeout: time.Minute,
+		timeout:     10 * time.Second,
+		decoder:     decodeQueueData,
+		inflight:    make(map[string]*sqlListLock),
+		logger:      manager.logger,
+	}
+	list.Reconfigure(options...)
+
+	return list, nil
+}
+
+// Migrate creates the table of the works when it doesn't exist
+func (list *SQLList) Migrate(ctx context.Context) error {
+	db := list.db.Get()
+	if db == nil {
+		return fmt.Errorf("the database of the sql list isn't connected [ table: %s ]", list.table)
+	}
+
+	var statements []string
+	switch sqlDialect(db) {
+	case sqlDialectPostgres:
+		statements = []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+				id BIGSERIAL PRIMARY KEY,
+				work_id VARCHAR(255) NOT NULL,
+				data TEXT NOT NULL,
+				is_work BOOLEAN NOT NULL DEFAULT TRUE,
+				status VARCHAR(20) NOT NULL,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				available_at TIMESTAMP NOT NULL,
+				locked_until TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL)`, list.table),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_status_idx ON %s (status, available_at)", strings.ReplaceAll(list.table, ".", "_"), list.table),
+		}
+	case sqlDialectMySQL:
+		statements = []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+				id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+				work_id VARCHAR(255) NOT NULL,
+				data LONGTEXT NOT NULL,
+				is_work BOOLEAN NOT NULL DEFAULT TRUE,
+				status VARCHAR(20) NOT NULL,
+				attempts INT NOT NULL DEFAULT 0,
+				available_at DATETIME(6) NOT NULL,
+				locked_until DATETIME(6) NULL,
+				created_at DATETIME(6) NOT NULL,
+				INDEX status_idx (status, available_at))`, list.table),
+		}
+	default:
+		return fmt.Errorf("the sql list needs postgres or mysql [ table: %s ]", list.table)
+	}
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("error migrating the sql list [ table: %s ]: %w", list.table, err)
+		}
+	}
+
+	return nil
+}
+
+// prepare gets the database, migrating the table on the first use
+func (list *SQLList) prepare(ctx context.Context) (*sql.DB, string, error) {
+	list.mux.Lock()
+	migrated := list.migrated
+	list.mux.Unlock()
+
+	if !migrated {
+		if err := list.Migrate(ctx); err != nil {
+			return nil, "", err
+		}
+
+		list.mux.Lock()
+		list.migrated = true
+		list.mux.Unlock()
+	}
+
+	db := list.db.Get()
+	if db == nil {
+		return nil, "", fmt.Errorf("the database of the sql list isn't connected [ table: %s ]", list.table)
+	}
+
+	return db, sqlDialect(db), nil
+}
+
+// query rewrites the placeholders of the query for the dialect
+func (list *SQLList) query(dialect, query string) string {
+	query = strings.ReplaceAll(query, "{table}", list.table)
+	if dialect != sqlDialectPostgres {
+		return query
+	}
+
+	var builder strings.Builder
+	count := 0
+	for _, char := range query {
+		if char == '?' {
+			count++
+			builder.WriteString("$" + strconv.Itoa(count))
+			continue
+		}
+		builder.WriteRune(char)
+	}
+
+	return builder.String()
+}
+
+// context ...
+func (list *SQLList) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), list.timeout)
+}
+
+// Add adds the work to the table or, when it was removed by this instance (ex: a retry), makes it available again
+func (list *SQLList) Add(id string, data interface{}) error {
+	ctx, cancel := list.context()
+	defer cancel()
+
+	db, dialect, err := list.prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	item, err := newListItem(id, data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = now
+	}
+
+	list.mux.Lock()
+	lock, exists := list.inflight[id]
+	delete(list.inflight, id)
+	list.mux.Unlock()
+
+	if exists {
+		result, err := db.ExecContext(ctx, list.query(dialect,
+			"UPDATE {table} SET status = ?, data = ?, available_at = ?, locked_until = NULL WHERE id = ? AND locked_until = ?"),
+			sqlListPending, string(item.Data), now.Add(list.retryDelay), lock.row, lock.lockedUntil)
+		if err != nil {
+			return fmt.Errorf("error adding the work to the sql list [ table: %s, id: %s ]: %w", list.table, id, err)
+		}
+
+		// the work removed by other worker after the lock ended is left to that worker
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			list.logger.Warnf("the lock of the work ended before it was added again [ table: %s, id: %s ]", list.table, id)
+		}
+
+		return nil
+	}
+
+	if _, err = db.ExecContext(ctx, list.query(dialect,
+		"INSERT INTO {table} (work_id, data, is_work, status, attempts, available_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"),
+		id, string(item.Data), item.Work, sqlListPending, item.Retries, now, item.CreatedAt.UTC()); err != nil {
+		return fmt.Errorf("error adding the work to the sql list [ table: %s, id: %s ]: %w", list.table, id, err)
+	}
+
+	return nil
+}
+
+// Remove locks the next available work or, with ids, deletes the pending works with the ids
+func (list *SQLList) Remove(ids ...string) interface{} {
+	if len(ids) > 0 {
+		return list.removeIds(ids...)
+	}
+
+	data, err := list.claim()
+	if err != nil {
+		list.logger.Errorf("error removing the work from the sql list [ table: %s ]: %s", list.table, err)
+		return nil
+	}
+
+	return data
+}
+
+// claim locks the next work available, that is pending or with its lock ended, in a transaction
+func (list *SQLList) claim() (_ interface{}, err error) {
+	ctx, cancel := list.context()
+	defer cancel()
+
+	db, dialect, err := list.prepare(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the works added again are available after the works waiting, as on the queue
+	order := "available_at ASC, id ASC"
+	if list.mode == LIFO {
+		order = "available_at DESC, id DESC"
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now().UTC()
+	item := &listItem{}
+	var row int64
+	var data string
+
+	err = tx.QueryRowContext(ctx, list.query(dialect,
+		"SELECT id, work_id, data, is_work, attempts, created_at FROM {table} "+
+			"WHERE "+sqlListAvailable+" "+
+			"ORDER BY "+order+" LIMIT 1 FOR UPDATE SKIP LOCKED"),
+		sqlListPending, now, sqlListProcessing, now).
+		Scan(&row, &item.ID, &data, &item.Work, &item.Retries, &item.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			tx.Rollback()
+			return nil, nil
+		}
+		return nil, err
+	}
+	item.Data = json.RawMessage(data)
+
+	if list.maxAttempts > 0 && item.Retries >= list.maxAttempts {
+		if _, err = tx.ExecContext(ctx, list.query(dialect, "DELETE FROM {table} WHERE id = ?"), row); err != nil {
+			return nil, err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return nil, err
+		}
+
+		list.logger.Errorf("work discarded of the sql list, it exceeded the attempts [ table: %s, id: %s, attempts: %d ]", list.table, item.ID, item.Retries)
+		return list.claim()
+	}
+
+	// the lock is truncated to the precision of the databases, to be compared
+	lock := &sqlListLock{row: row, lockedUntil: now.Add(list.lockTimeout).Truncate(time.Microsecond)}
+	if _, err = tx.ExecContext(ctx, list.query(dialect, "UPDATE {table} SET status = ?, attempts = attempts + 1, locked_until = ? WHERE id = ?"),
+		sqlListProcessing, lock.lockedUntil, row); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	restored, err := item.restore(list.decoder, list.logger)
+	if err != nil {
+		list.logger.Errorf("error restoring the work of the sql list, discarding it [ table: %s ]: %s", list.table, err)
+		if _, errDelete := db.ExecContext(ctx, list.query(dialect, "DELETE FROM {table} WHERE id = ?"), row); errDelete != nil {
+			list.logger.Errorf("error discarding the work of the sql list [ table: %s, id: %s ]: %s", list.table, item.ID, errDelete)
+		}
+		return nil, nil
+	}
+
+	list.mux.Lock()
+	list.inflight[item.ID] = lock
+	list.mux.Unlock()
+
+	return restored, nil
+}
+
+// removeIds deletes the pending works with the ids
+func (list *SQLList) removeIds(ids ...string) interface{} {
+	ctx, cancel := list.context()
+	defer cancel()
+
+	db, dialect, err := list.prepare(ctx)
+	if err != nil {
+		list.logger.Errorf("error removing the works from the sql list [ table: %s ]: %s", list.table, err)
+		return nil
+	}
+
+	var removed []interface{}
+	for _, id := range ids {
+		works, err := list.removeId(ctx, db, dialect, id)
+		if err != nil {
+			list.logger.Errorf("error removing the work from the sql list [ table: %s, id: %s ]: %s", list.table, id, err)
+			continue
+		}
+		removed = append(removed, works...)
+	}
+
+	return removed
+}
+
+// removeId deletes the pending works with the id, in a transaction
+func (list *SQLList) removeId(ctx context.Context, db *sql.DB, dialect, id string) (_ []interface{}, err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	rows, err := tx.QueryContext(ctx, list.query(dialect,
+		"SELECT id, work_id, data, is_work, attempts, created_at FROM {table} WHERE work_id = ? AND status = ? FOR UPDATE SKIP LOCKED"), id, sqlListPending)
+	if err != nil {
+		return nil, err
+	}
+	removed, deleted := list.scan(rows)
+	if len(deleted) == 0 {
+		return nil, tx.Rollback()
+	}
+
+	for _, row := range deleted {
+		if _, err = tx.ExecContext(ctx, list.query(dialect, "DELETE FROM {table} WHERE id = ?"), row); err != nil {
+			return nil, err
+		}
+	}
+
+	return removed, tx.Commit()
+}
+
+// scan restores the works of the rows, returning them with their rows
+func (list *SQLList) scan(rows *sql.Rows) ([]interface{}, []int64) {
+	defer rows.Close()
+
+	var works []interface{}
+	var ids []int64
+	for rows.Next() {
+		item := &listItem{}
+		var row int64
+		var data string
+		if err := rows.Scan(&row, &item.ID, &data, &item.Work, &item.Retries, &item.CreatedAt); err != nil {
+			list.logger.Errorf("error reading the work of the sql list [ table: %s ]: %s", list.table, err)
+			continue
+		}
+		item.Data = json.RawMessage(data)
+
+		if work, err := item.restore(list.decoder, list.logger); err == nil {
+			works = append(works, work)
+			ids = append(ids, row)
+		}
+	}
+
+	return works, ids
+}
+
+// Ack acknowledges the works removed by this instance, deleting them while they have the same lock
+func (list *SQLList) Ack(ids ...string) error {
+	ctx, cancel := list.context()
+	defer cancel()
+
+	db, dialect, err := list.prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		list.mux.Lock()
+		lock, exists := list.inflight[id]
+		delete(list.inflight, id)
+		list.mux.Unlock()
+
+		if !exists {
+			continue
+		}
+
+		if _, err = db.ExecContext(ctx, list.query(dialect, "DELETE FROM {table} WHERE id = ? AND locked_until = ?"), lock.row, lock.lockedUntil); err != nil {
+			return fmt.Errorf("error acknowledging the work of the sql list [ table: %s, id: %s ]: %w", list.table, id, err)
+		}
+	}
+
+	return nil
+}
+
+// count counts the works by status, for the dump
+func (list *SQLList) count() (available, pending, processing int, err error) {
+	ctx, cancel := list.context()
+	defer cancel()
+
+	db, dialect, err := list.prepare(ctx)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	now := time.Now().UTC()
+	err = db.QueryRowContext(ctx, list.query(dialect,
+		"SELECT "+
+			"COALESCE(SUM(CASE WHEN "+sqlListAvailable+" THEN 1 ELSE 0 END), 0), "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0), "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) "+
+			"FROM {table}"),
+		sqlListPending, now, sqlListProcessing, now, sqlListPending, sqlListProcessing).
+		Scan(&available, &pending, &processing)
+
+	return available, pending, processing, err
+}
+
+// Size gets the number of works available, that are pending or with their lock ended
+func (list *SQLList) Size() int {
+	ctx, cancel := list.context()
+	defer cancel()
+
+	db, dialect, err := list.prepare(ctx)
+	if err != nil {
+		list.logger.Errorf("error getting the size of the sql list [ table: %s ]: %s", list.table, err)
+		return 0
+	}
+
+	now := time.Now().UTC()
+	var size int
+	if err = db.QueryRowContext(ctx, list.query(dialect, "SELECT COUNT(*) FROM {table} WHERE "+sqlListAvailable),
+		sqlListPending, now, sqlListProcessing, now).Scan(&size); err != nil {
+		list.logger.Errorf("error getting the size of the sql list [ table: %s ]: %s", list.table, err)
+		return 0
+	}
+
+	return size
+}
+
+// IsEmpty checks if there is a work available, without counting them
+func (list *SQLList) IsEmpty() bool {
+	ctx, cancel := list.context()
+	defer cancel()
+
+	db, dialect, err := list.prepare(ctx)
+	if err != nil {
+		list.logger.Errorf("error checking the works of the sql list [ table: %s ]: %s", list.table, err)
+		return true
+	}
+
+	now := time.Now().UTC()
+	var exists int
+	err = db.QueryRowContext(ctx, list.query(dialect, "SELECT 1 FROM {table} WHERE "+sqlListAvailable+" LIMIT 1"),
+		sqlListPending, now, sqlListProcessing, now).Scan(&exists)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			list.logger.Errorf("error checking the works of the sql list [ table: %s ]: %s", list.table, err)
+		}
+		return true
+	}
+
+	return false
+}
+
+// Dump ...
+func (list *SQLList) Dump() string {
+	type sqlListPrint struct {
+		Table       string        `json:"table"`
+		Mode        Mode          `json:"mode"`
+		Size        int           `json:"size"`
+		Pending     int           `json:"pending"`
+		Processing  int           `json:"processing"`
+		LockTimeout time.Duration `json:"lock_timeout"`
+	}
+
+	print := sqlListPrint{
+		Table:       list.table,
+		Mode:        list.mode,
+		LockTimeout: list.lockTimeout,
+	}
+
+	var err error
+	if print.Size, print.Pending, print.Processing, err = list.count(); err != nil {
+		list.logger.Error(err)
+		return ""
+	}
+
+	if json, err := json.Marshal(print); err != nil {
+		list.logger.Error(err)
+		return ""
+	} else {
+		return string(json)
+	}
+}
@@ -0,0 +1,370 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// fakeSQLRow is a row of the table of the fake database
+type fakeSQLRow struct {
+	id          int64
+	workID      string
+	data        string
+	isWork      bool
+	status      string
+	attempts    int64
+	availableAt time.Time
+	lockedUntil *time.Time
+	createdAt   time.Time
+}
+
+// fakeSQLTable keeps the table of the sql list in memory, answering the queries of the sql list on postgres
+type fakeSQLTable struct {
+	rows   []*fakeSQLRow
+	nextID int64
+	mux    sync.Mutex
+}
+
+// available checks the condition of the works available (sqlListAvailable)
+func (row *fakeSQLRow) available(args []driver.NamedValue) bool {
+	return (row.status == args[0].Value.(string) && !row.availableAt.After(args[1].Value.(time.Time))) ||
+		(row.status == args[2].Value.(string) && row.lockedUntil != nil && !row.lockedUntil.After(args[3].Value.(time.Time)))
+}
+
+func (table *fakeSQLTable) exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	table.mux.Lock()
+	defer table.mux.Unlock()
+
+	affected := int64(0)
+	switch {
+	case strings.HasPrefix(query, "CREATE"):
+
+	case strings.HasPrefix(query, "INSERT"):
+		table.nextID++
+		table.rows = append(table.rows, &fakeSQLRow{
+			id:          table.nextID,
+			workID:      args[0].Value.(string),
+			data:        args[1].Value.(string),
+			isWork:      args[2].Value.(bool),
+			status:      args[3].Value.(string),
+			attempts:    args[4].Value.(int64),
+			availableAt: args[5].Value.(time.Time),
+			createdAt:   args[6].Value.(time.Time),
+		})
+		affected = 1
+
+	case strings.Contains(query, "attempts = attempts + 1"):
+		for _, row := range table.rows {
+			if row.id == args[2].Value.(int64) {
+				lockedUntil := args[1].Value.(time.Time)
+				row.status, row.attempts, row.lockedUntil = args[0].Value.(string), row.attempts+1, &lockedUntil
+				affected++
+			}
+		}
+
+	case strings.HasPrefix(query, "UPDATE"):
+		for _, row := range table.rows {
+			if row.id == args[3].Value.(int64) && row.lockedUntil != nil && row.lockedUntil.Equal(args[4].Value.(time.Time)) {
+				row.status, row.data, row.availableAt, row.lockedUntil = args[0].Value.(string), args[1].Value.(string), args[2].Value.(time.Time), nil
+				affected++
+			}
+		}
+
+	case strings.HasPrefix(query, "DELETE"):
+		var kept []*fakeSQLRow
+		for _, row := range table.rows {
+			if row.id == args[0].Value.(int64) && (len(args) == 1 || (row.lockedUntil != nil && row.lockedUntil.Equal(args[1].Value.(time.Time)))) {
+				affected++
+				continue
+			}
+			kept = append(kept, row)
+		}
+		table.rows = kept
+
+	default:
+		return nil, fmt.Errorf("unexpected statement: %s", query)
+	}
+
+	return driver.RowsAffected(affected), nil
+}
+
+func (table *fakeSQLTable) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	table.mux.Lock()
+	defer table.mux.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT COUNT(*)"):
+		count := int64(0)
+		for _, row := range table.rows {
+			if row.available(args) {
+				count++
+			}
+		}
+		return &fakeSQLRows{columns: []string{"count"}, values: [][]driver.Value{{count}}}, nil
+
+	case strings.HasPrefix(query, "SELECT 1"):
+		rows := &fakeSQLRows{columns: []string{"exists"}}
+		for _, row := range table.rows {
+			if row.available(args) {
+				rows.values = append(rows.values, []driver.Value{int64(1)})
+				break
+			}
+		}
+		return rows, nil
+
+	case strings.Contains(query, "work_id = $1"):
+		var found []*fakeSQLRow
+		for _, row := range table.rows {
+			if row.workID == args[0].Value.(string) && row.status == args[1].Value.(string) {
+				found = append(found, row)
+			}
+		}
+		return newFakeSQLWorkRows(found), nil
+
+	case strings.Contains(query, "FOR UPDATE SKIP LOCKED"):
+		var found []*fakeSQLRow
+		for _, row := range table.rows {
+			if row.available(args) {
+				found = append(found, row)
+			}
+		}
+
+		descending := strings.Contains(query, "DESC")
+		sort.Slice(found, func(i, j int) bool {
+			if !found[i].availableAt.Equal(found[j].availableAt) {
+				return found[i].availableAt.Before(found[j].availableAt) != descending
+			}
+			return (found[i].id < found[j].id) != descending
+		})
+
+		if len(found) > 1 {
+			found = found[:1]
+		}
+		return newFakeSQLWorkRows(found), nil
+
+	default:
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+}
+
+// row gets the row of the work with the id
+func (table *fakeSQLTable) row(workID string) *fakeSQLRow {
+	table.mux.Lock()
+	defer table.mux.Unlock()
+
+	for _, row := range table.rows {
+		if row.workID == workID {
+			return row
+		}
+	}
+
+	return nil
+}
+
+// fakeSQLRows ...
+type fakeSQLRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func newFakeSQLWorkRows(found []*fakeSQLRow) *fakeSQLRows {
+	rows := &fakeSQLRows{columns: []string{"id", "work_id", "data", "is_work", "attempts", "created_at"}}
+	for _, row := range found {
+		rows.values = append(rows.values, []driver.Value{row.id, row.workID, row.data, row.isWork, row.attempts, row.createdAt})
+	}
+
+	return rows
+}
+
+func (rows *fakeSQLRows) Columns() []string { return rows.columns }
+
+func (rows *fakeSQLRows) Close() error { return nil }
+
+func (rows *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(rows.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, rows.values[0])
+	rows.values = rows.values[1:]
+	return nil
+}
+
+// fakeSQLConn is a connection to the table, the transactions aren't isolated
+type fakeSQLConn struct {
+	table *fakeSQLTable
+}
+
+func (conn *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements aren't supported")
+}
+
+func (conn *fakeSQLConn) Close() error { return nil }
+
+func (conn *fakeSQLConn) Begin() (driver.Tx, error) { return conn, nil }
+
+func (conn *fakeSQLConn) Commit() error { return nil }
+
+func (conn *fakeSQLConn) Rollback() error { return nil }
+
+func (conn *fakeSQLConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return conn.table.exec(query, args)
+}
+
+func (conn *fakeSQLConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return conn.table.query(query, args)
+}
+
+// fakeSQLConnector connects to the table with the postgres driver, so that the sql list uses the postgres dialect
+type fakeSQLConnector struct {
+	table *fakeSQLTable
+}
+
+func (connector *fakeSQLConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeSQLConn{table: connector.table}, nil
+}
+
+func (connector *fakeSQLConnector) Driver() driver.Driver { return &pq.Driver{} }
+
+// fakeDB ...
+type fakeDB struct {
+	db *sql.DB
+}
+
+func (db *fakeDB) Start(ctx context.Context) error { return nil }
+
+func (db *fakeDB) Stop(ctx context.Context) error { return db.db.Close() }
+
+func (db *fakeDB) Started() bool { return true }
+
+func (db *fakeDB) Get() *sql.DB { return db.db }
+
+func newFakeSQLList(t *testing.T, m *Manager, options ...SQLListOption) (*SQLList, *fakeSQLTable) {
+	t.Helper()
+
+	table := &fakeSQLTable{}
+	db := &fakeDB{db: sql.OpenDB(&fakeSQLConnector{table: table})}
+	t.Cleanup(func() { db.Stop(context.Background()) })
+
+	list, err := m.NewSQLList(db, "works", options...)
+	if err != nil {
+		t.Fatalf("error creating the sql list: %s", err)
+	}
+
+	return list.(*SQLList), table
+}
+
+func TestSQLListClaim(t *testing.T) {
+	tests := []struct {
+		name             string
+		options          []SQLListOption
+		removes          int
+		ack              bool
+		expected         []string
+		expectedAttempts int64
+		expectedStatus   string
+		expectedSize     int
+	}{
+		{name: "claim locks the work", removes: 1, expected: []string{"a"}, expectedAttempts: 1, expectedStatus: sqlListProcessing, expectedSize: 1},
+		{name: "claim on lifo", options: []SQLListOption{WithSQLMode(LIFO)}, removes: 1, expected: []string{"b"}, expectedAttempts: 1, expectedStatus: sqlListProcessing, expectedSize: 1},
+		{name: "locked works aren't claimed again", removes: 3, expected: []string{"a", "b", ""}, expectedAttempts: 1, expectedStatus: sqlListProcessing},
+		{name: "works with the lock ended are claimed again", options: []SQLListOption{WithLockTimeout(time.Millisecond)}, removes: 2, expected: []string{"a", "a"}, expectedAttempts: 2, expectedStatus: sqlListProcessing, expectedSize: 2},
+		{name: "works over the attempts are discarded", options: []SQLListOption{WithLockTimeout(time.Millisecond), WithMaxAttempts(1)}, removes: 3, expected: []string{"a", "b", ""}},
+		{name: "acknowledged works are deleted", removes: 1, ack: true, expected: []string{"a"}, expectedSize: 1},
+	}
+
+	m := NewManager()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list, table := newFakeSQLList(t, m, test.options...)
+
+			for _, id := range []string{"a", "b"} {
+				if err := list.Add(id, NewWork(id, "data-"+id, m.logger)); err != nil {
+					t.Fatalf("error adding the work %s: %s", id, err)
+				}
+				// the works are ordered by the time they are available
+				time.Sleep(time.Millisecond)
+			}
+
+			var removed []string
+			for i := 0; i < test.removes; i++ {
+				id := ""
+				if work, ok := list.Remove().(*Work); ok {
+					id = work.Id
+				}
+				removed = append(removed, id)
+				time.Sleep(2 * time.Millisecond)
+			}
+
+			if fmt.Sprint(removed) != fmt.Sprint(test.expected) {
+				t.Fatalf("removed the works %q, expected %q", removed, test.expected)
+			}
+
+			if test.ack {
+				if err := list.Ack(removed[0]); err != nil {
+					t.Fatalf("error acknowledging the work: %s", err)
+				}
+			}
+
+			row := table.row(test.expected[0])
+			switch {
+			case test.expectedStatus == "" && row != nil && row.status != sqlListPending:
+				t.Errorf("the work %s wasn't deleted [ status: %s ]", test.expected[0], row.status)
+			case test.expectedStatus != "" && row == nil:
+				t.Errorf("the work %s was deleted", test.expected[0])
+			case test.expectedStatus != "" && (row.status != test.expectedStatus || row.attempts != test.expectedAttempts):
+				t.Errorf("the work %s has the status %s and the attempts %d, expected %s and %d", test.expected[0], row.status, row.attempts, test.expectedStatus, test.expectedAttempts)
+			}
+
+			if size := list.Size(); size != test.expectedSize {
+				t.Errorf("the list has %d works, expected %d", size, test.expectedSize)
+			}
+
+			if list.IsEmpty() != (test.expectedSize == 0) {
+				t.Errorf("the list is empty %t, expected %t", list.IsEmpty(), test.expectedSize == 0)
+			}
+		})
+	}
+}
+
+func TestSQLListRetry(t *testing.T) {
+	m := NewManager()
+	list, table := newFakeSQLList(t, m)
+
+	list.Add("a", NewWork("a", "data-a", m.logger))
+
+	work := list.Remove().(*Work)
+	work.retries++
+	if err := list.Add(work.Id, work); err != nil {
+		t.Fatalf("error adding the work again: %s", err)
+	}
+
+	if len(table.rows) != 1 || table.rows[0].status != sqlListPending {
+		t.Fatalf("the work added again wasn't made available on its row")
+	}
+
+	retried := list.Remove().(*Work)
+	if retried.Id != "a" || retried.retries != 1 || retried.Data != "data-a" {
+		t.Errorf("removed the work %s with the retries %d and the data %v, expected a with 1 and data-a", retried.Id, retried.retries, retried.Data)
+	}
+
+	// the work is acknowledged with its last lock
+	if err := list.Ack("a"); err != nil {
+		t.Fatalf("error acknowledging the work: %s", err)
+	}
+
+	if len(table.rows) != 0 {
+		t.Errorf("the acknowledged work wasn't deleted")
+	}
+}
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/joaosoft/logger"
@@ -31,3 +33,66 @@ func NewWork(id string, data interface{}, logger logger.ILogger) *Work {
 func (work *Work) ElapsedTime() time.Duration {
 	return time.Since(work.createdAt)
 }
+
+// QueueDecoder decodes the data of the works restored by the lists that keep the works out of memory
+type QueueDecoder func(data json.RawMessage) (interface{}, error)
+
+// listItem is a work encoded by the lists that keep the works out of memory, with the data as json
+type listItem struct {
+	ID        string          `json:"id"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Work      bool            `json:"work,omitempty"`
+	Retries   int             `json:"retries,omitempty"`
+	CreatedAt time.Time       `json:"created_at,omitempty"`
+}
+
+// newListItem encodes the data added to a list, keeping the retries of the works
+func newListItem(id string, data interface{}) (*listItem, error) {
+	item := &listItem{ID: id}
+
+	value := data
+	if work, ok := data.(*Work); ok {
+		item.Work = true
+		item.Retries = work.retries
+		item.CreatedAt = work.createdAt
+		value = work.Data
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding the work [ id: %s ]: %w", id, err)
+	}
+	item.Data = encoded
+
+	return item, nil
+}
+
+// restore rebuilds the data added to the list
+func (item *listItem) restore(decoder QueueDecoder, logger logger.ILogger) (interface{}, error) {
+	data, err := decoder(item.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the work [ id: %s ]: %w", item.ID, err)
+	}
+
+	if !item.Work {
+		return data, nil
+	}
+
+	work := NewWork(item.ID, data, logger)
+	work.retries = item.Retries
+	if !item.CreatedAt.IsZero() {
+		work.createdAt = item.CreatedAt
+	}
+
+	return work, nil
+}
+
+// decodeQueueData is the default decoder of the works restored, with the data decoded as json
+func decodeQueueData(data json.RawMessage) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"io"
 	"sync"
 	"time"
 
@@ -36,9 +37,10 @@ type Worker struct {
 	maxRetries                      int
 	sleepTime                       time.Duration
 	quit                            chan bool
+	done                            chan bool
 	mux                             *sync.Mutex
 	logger                          logger.ILogger
-	started                         bool
+	state                           *StateMachine
 }
 
 // NewWorker ...
@@ -52,9 +54,9 @@ func NewWorker(id int, config *WorkListConfig, handler WorkHandler, list IList,
 		workRecoverHandler:              workRecoverHandler,
 		workRecoverWastedRetriesHandler: workRecoverWastedRetriesHandler,
 		list:                            list,
-		quit:                            make(chan bool),
 		mux:                             &sync.Mutex{},
 		logger:                          logger,
+		state:                           NewStateMachine(),
 	}
 
 	return worker
@@ -62,15 +64,29 @@ func NewWorker(id int, config *WorkListConfig, handler WorkHandler, list IList,
 
 // Start ...
 func (worker *Worker) Start() error {
-	go func() error {
+	worker.mux.Lock()
+	defer worker.mux.Unlock()
+
+	if !worker.state.start() {
+		return nil
+	}
+
+	quit := make(chan bool)
+	done := make(chan bool)
+	worker.quit = quit
+	worker.done = done
+
+	go func() {
+		defer close(done)
+
 		for {
 			select {
-			case <-worker.quit:
+			case <-quit:
 				logger.Debugf("worker quited [name: %s, list size: %d ]", worker.name, worker.list.Size())
 
-				return nil
+				return
 			default:
-				if worker.list.Size() > 0 {
+				if !worker.list.IsEmpty() {
 					logger.Debugf("worker starting [ name: %d, queue size: %d]", worker.name, worker.list.Size())
 					if err := worker.execute(); err != nil {
 						logger.Errorf("worker errored [ name: %s, queue size: %d]", worker.name, worker.list.Size())
@@ -78,29 +94,40 @@ func (worker *Worker) Start() error {
 					logger.Debugf("worker finished [ name: %s, queue size: %d]", worker.name, worker.list.Size())
 				} else {
 					logger.Debugf("worker waiting for work to do... [ id: %d, name: %s ]", worker.id, worker.name)
-					<-time.After(worker.sleepTime)
+					select {
+					case <-quit:
+						logger.Debugf("worker quited [name: %s, list size: %d ]", worker.name, worker.list.Size())
+
+						return
+					case <-time.After(worker.sleepTime):
+					}
 				}
 			}
 		}
 	}()
 
-	worker.started = true
+	worker.state.started(nil)
 
 	return nil
 }
 
-// Stop ...
+// Stop signals the worker to quit and waits for the work being done, without holding the lock while waiting
 func (worker *Worker) Stop() error {
-
 	worker.mux.Lock()
-	defer worker.mux.Unlock()
+	if !worker.state.stop() {
+		worker.mux.Unlock()
+		return nil
+	}
 
-	if worker.list.Size() > 0 {
+	if !worker.list.IsEmpty() {
 		logger.Infof("stopping worker with tasks in the list [ list size: %d ]", worker.list.Size())
 	}
-	worker.quit <- true
+	close(worker.quit)
+	done := worker.done
+	worker.mux.Unlock()
 
-	worker.started = false
+	<-done
+	worker.state.stopped(nil)
 
 	return nil
 }
@@ -125,9 +152,12 @@ func (worker *Worker) execute() error {
 		}
 	}()
 
-	if tmp := worker.list.Remove(); tmp != nil {
-		work = tmp.(*Work)
+	// the work can be removed by other workers after the size is checked, as on the shared lists
+	tmp := worker.list.Remove()
+	if tmp == nil {
+		return nil
 	}
+	work = tmp.(*Work)
 
 	if err := worker.handler(work.Id, work.Data); err != nil {
 		if work.retries < worker.maxRetries {
@@ -144,11 +174,39 @@ func (worker *Worker) execute() error {
 				}
 			}
 			logger.Errorf("work discarded of the queue [ retries: %d, error: %s ]", work.retries, err).ToError()
-
+			ackWorks(worker.list, work.Id)
 		}
 
 		return nil
 	}
+	ackWorks(worker.list, work.Id)
 
 	return nil
 }
+
+// openList opens the list again when it was closed by a stop, so that the work list can be started again
+func openList(list IList) error {
+	if opener, ok := list.(interface{ open() error }); ok {
+		return opener.open()
+	}
+
+	return nil
+}
+
+// closeList closes the list when the work list is stopped (ex: the log of a queue)
+func closeList(list IList) {
+	if closer, ok := list.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logger.Errorf("error closing the list of the work list [ error: %s ]", err)
+		}
+	}
+}
+
+// ackWorks acknowledges the works done or discarded, when the list keeps them until then
+func ackWorks(list IList, ids ...string) {
+	if ackList, ok := list.(IAckList); ok {
+		if err := ackList.Ack(ids...); err != nil {
+			logger.Errorf("error acknowledging the works of the queue [ ids: %v, error: %s ]", ids, err)
+		}
+	}
+}